@@ -0,0 +1,199 @@
+package when
+
+import "time"
+
+// Expr is a parsed expression. It is evaluated against a reference time
+// by Parser.Eval, so a parsed expression may be evaluated repeatedly.
+//
+// An expression is an anchor, the date and time of day it refers to,
+// with durations applied before and after it. For example,
+// "2 days before Jan 2nd at 3pm + 1 hour" has the terms "2 days", the
+// date "Jan 2nd", the clock "3pm" and the offset "1 hour".
+type Expr struct {
+	Input   string // the parsed expression
+	Terms   []Term // durations preceding the anchor
+	Sub     bool   // terms are subtracted from the anchor, as in "ago" or "before"
+	Date    Date   // date of the anchor, or nil
+	Clock   *Clock // time of day of the anchor, or nil
+	Offsets []Term // durations following the anchor
+
+	// Location is the time zone of the anchor, or nil for the location
+	// of the reference time.
+	Location *time.Location
+}
+
+// IsNow reports whether the anchor of the expression is the reference time.
+func (e *Expr) IsNow() bool {
+	return e.Date == nil && e.Clock == nil
+}
+
+// Term is a signed duration in a unit of granularity.
+type Term struct {
+	N    int
+	Unit Granularity
+}
+
+// Clock is a time of day. Precision is the finest unit written, so "3pm"
+// has a precision of Hour while "3:00pm" has a precision of Minute.
+type Clock struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+	Precision  Granularity
+}
+
+// add moves the clock by the given number of minutes, wrapping around
+// midnight.
+func (c *Clock) add(minutes int) {
+	n := ((c.Hour*60+c.Minute+minutes)%1440 + 1440) % 1440
+	c.Hour, c.Minute = n/60, n%60
+	if c.Precision < Minute {
+		c.Precision = Minute
+	}
+}
+
+// Span is the byte range of a node within the parsed input.
+type Span struct {
+	Pos int
+	End int
+}
+
+// Date is the date of an anchor. It is one of *RelativeDay,
+// *CalendarDate, *WeekDate, *MonthDate, *DayOfMonth, *WeekdayDate,
+// *NthWeekday, *HolidayDate, *PeriodDate or *BoundaryDate.
+type Date interface {
+	span() Span
+	resolve(e *env) (time.Time, Granularity, error)
+}
+
+func (s Span) span() Span {
+	return s
+}
+
+// Qualifier modifies the resolution of a named weekday or month.
+type Qualifier int
+
+const (
+	// Nearest resolves to the nearest occurrence in the direction of the bias.
+	Nearest Qualifier = iota
+	// Next resolves according to the NextPolicy.
+	Next
+	// Last resolves to the most recent occurrence before today.
+	Last
+	// Upcoming resolves to the first occurrence after today.
+	Upcoming
+	// This resolves to the occurrence in the current week or year.
+	This
+)
+
+// MonthRef identifies the month of a date. If Month is non-zero the date
+// is in the nearest occurrence of that month in the direction of the
+// bias. Otherwise, if Relative is set the date is Offset months from the
+// current month, and if not the date is in the nearest month in which
+// it occurs.
+type MonthRef struct {
+	Month    time.Month
+	Relative bool
+	Offset   int
+}
+
+// RelativeDay is a date a number of days from today, as in "today",
+// "tomorrow" and "yesterday".
+type RelativeDay struct {
+	Span
+	Days int
+}
+
+// CalendarDate is an absolute date, as in "2006", "2006-01" and
+// "2006-01-02". Month and Day are zero when they are omitted.
+type CalendarDate struct {
+	Span
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// WeekDate is an ISO 8601 week date, as in "2006-W01-2" and "2006-W01".
+// Week 1 is the week containing the first Thursday of the year. Day is
+// the ISO weekday, from 1 for Monday to 7 for Sunday, or zero for the
+// whole week.
+type WeekDate struct {
+	Span
+	Year int
+	Week int
+	Day  int
+}
+
+// MonthDate is the first day of a named month, as in "March",
+// "next March" and "this March".
+type MonthDate struct {
+	Span
+	Month     time.Month
+	Qualifier Qualifier
+}
+
+// DayOfMonth is a day of a month, as in "the 4th", "March 14th" and
+// "4th of next month". A negative Day counts back from the end of the
+// month, so "last day of the month" is -1.
+type DayOfMonth struct {
+	Span
+	Day   int
+	Month MonthRef
+}
+
+// WeekdayDate is a named weekday, as in "Friday", "next Friday",
+// "last Friday" and "this Friday".
+type WeekdayDate struct {
+	Span
+	Weekday   time.Weekday
+	Qualifier Qualifier
+}
+
+// NthWeekday is an occurrence of a weekday within a month, as in
+// "2nd Tuesday of March". A negative N counts back from the end of the
+// month, so "last Tuesday of the month" is -1.
+type NthWeekday struct {
+	Span
+	N       int
+	Weekday time.Weekday
+	Month   MonthRef
+}
+
+// HolidayDate is a named holiday, as in "Christmas" and
+// "Thanksgiving 2025". Year is zero when it is omitted. The date is
+// provided by the HolidayProvider of the parser it is evaluated by.
+type HolidayDate struct {
+	Span
+	Name string
+	Year int
+}
+
+// PeriodDate is the start of a period, as in "Q3", "H1 2025",
+// "next quarter", "this month", "the week after next" and "FY2026".
+// Unit is Quarter, Half or Year and N is the quarter or half of the
+// year, from 1, unless the period is relative. Year is zero when it is
+// omitted. Relative periods of any unit from Year to Second are Offset
+// periods from the one containing the reference time. Fiscal periods are
+// counted from the first month of the fiscal year, and fiscal years are
+// named by the calendar year they end in.
+type PeriodDate struct {
+	Span
+	Unit     Granularity
+	N        int
+	Year     int
+	Relative bool
+	Offset   int
+	Fiscal   bool
+}
+
+// BoundaryDate is the start or end of the period of a date, as in
+// "start of next week", "end of March" and "EOD". Without a time of day
+// the start is midnight and the end is the last instant of the period.
+// A date resolved by the bias keeps the period containing the reference
+// time, so "end of March" is this March until it is over.
+type BoundaryDate struct {
+	Span
+	Date Date
+	End  bool
+}
@@ -0,0 +1,160 @@
+package when
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseExpr(t *testing.T) {
+	tests := []struct {
+		in   string
+		want *Expr
+	}{
+		{
+			"",
+			&Expr{},
+		},
+		{
+			"now + 2 days",
+			&Expr{Offsets: []Term{{2, Day}}},
+		},
+		{
+			"6 hours ago",
+			&Expr{Terms: []Term{{6, Hour}}, Sub: true},
+		},
+		{
+			"1y 2M before tomorrow at noon",
+			&Expr{
+				Terms: []Term{{1, Year}, {2, Month}},
+				Sub:   true,
+				Date:  &RelativeDay{Span{13, 21}, 1},
+				Clock: &Clock{Hour: 12, Precision: Hour},
+			},
+		},
+		{
+			"2006-01-02 15:04:05 - 3h",
+			&Expr{
+				Date:    &CalendarDate{Span{0, 10}, 2006, time.January, 2},
+				Clock:   &Clock{Hour: 15, Minute: 4, Second: 5, Precision: Second},
+				Offsets: []Term{{-3, Hour}},
+			},
+		},
+		{
+			"4pm on the 4th of next month",
+			&Expr{
+				Date:  &DayOfMonth{Span{11, 28}, 4, MonthRef{Relative: true, Offset: 1}},
+				Clock: &Clock{Hour: 16, Precision: Hour},
+			},
+		},
+		{
+			"March 14th",
+			&Expr{Date: &DayOfMonth{Span{0, 10}, 14, MonthRef{Month: time.March}}},
+		},
+		{
+			"next friday",
+			&Expr{Date: &WeekdayDate{Span{0, 11}, time.Friday, Next}},
+		},
+		{
+			"2nd last tuesday of march",
+			&Expr{Date: &NthWeekday{Span{0, 25}, -2, time.Tuesday, MonthRef{Month: time.March}}},
+		},
+		{
+			"last day of the month",
+			&Expr{Date: &DayOfMonth{Span{0, 21}, -1, MonthRef{Relative: true}}},
+		},
+		{
+			"quarter to 4pm",
+			&Expr{Clock: &Clock{Hour: 15, Minute: 45, Precision: Minute}},
+		},
+	}
+	for _, tt := range tests {
+		tt.want.Input = tt.in
+		have, err := ParseExpr(tt.in)
+		if err != nil {
+			t.Errorf("ParseExpr(%q)\nunexpected error: %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("ParseExpr(%q)\nhave %#v\nwant %#v", tt.in, have, tt.want)
+		}
+	}
+}
+
+func TestEval(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e, err := ParseExpr("on the 4th at 9am + 1 hour")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		now  time.Time
+		want time.Time
+	}{
+		{
+			time.Date(2006, time.January, 2, 15, 4, 5, 0, loc),
+			time.Date(2006, time.January, 4, 10, 0, 0, 0, loc),
+		},
+		{
+			time.Date(2006, time.January, 4, 8, 0, 0, 0, loc),
+			time.Date(2006, time.January, 4, 10, 0, 0, 0, loc),
+		},
+		{
+			time.Date(2006, time.January, 4, 15, 4, 5, 0, loc),
+			time.Date(2006, time.February, 4, 10, 0, 0, 0, loc),
+		},
+	}
+	for _, tt := range tests {
+		have, err := Eval(e, tt.now)
+		if err != nil {
+			t.Errorf("Eval(%v)\nunexpected error: %v", tt.now, err)
+			continue
+		}
+		if !have.Equal(tt.want) {
+			t.Errorf("Eval(%v)\nhave %v\nwant %v", tt.now, have, tt.want)
+		}
+	}
+}
+
+func TestEvalStrict(t *testing.T) {
+	p := New(WithStrict())
+	e, err := p.ParseExpr("on the 31st")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.February, 2, 15, 4, 5, 0, time.UTC)
+	_, err = p.Eval(e, now)
+	perr, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("Eval(%v)\nhave %v\nwant *ParseError", now, err)
+	}
+	if perr.Pos != 7 || perr.End != 11 || perr.Value != "31st" {
+		t.Errorf("Eval(%v)\nhave %d:%d %q\nwant 7:11 \"31st\"", now, perr.Pos, perr.End, perr.Value)
+	}
+	now = time.Date(2006, time.March, 2, 15, 4, 5, 0, time.UTC)
+	_, err = p.Eval(e, now)
+	if err != nil {
+		t.Errorf("Eval(%v)\nunexpected error: %v", now, err)
+	}
+}
+
+func TestEvalStrictBuiltExpr(t *testing.T) {
+	p := New(WithStrict())
+	e := &Expr{Date: &CalendarDate{Span: Span{5, 10}, Year: 2024, Month: time.February, Day: 30}}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	_, err := p.Eval(e, now)
+	perr, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("Eval(%v)\nhave %v\nwant *ParseError", now, err)
+	}
+	if perr.Error() == "" || perr.Caret() == "" || perr.Column() != 1 {
+		t.Errorf("Eval(%v)\nhave %q column %d", now, perr.Error(), perr.Column())
+	}
+	perr.setInput("Feb")
+	if perr.Value != "" || perr.Column() != 4 {
+		t.Errorf("setInput\nhave %q column %d\nwant \"\" column 4", perr.Value, perr.Column())
+	}
+}
@@ -0,0 +1,149 @@
+package when
+
+import (
+	"errors"
+	"time"
+)
+
+// Calendar defines the working time counted by business days and working
+// hours.
+type Calendar interface {
+	// Workday reports whether the date of t is a working day.
+	Workday(t time.Time) bool
+	// WorkingHours returns the start and end of the working hours on the
+	// date of t.
+	WorkingHours(t time.Time) (start, end time.Time)
+}
+
+// WorkWeek is a Calendar with the same working hours on each working day
+// of the week.
+type WorkWeek struct {
+	// Days are the working days of the week.
+	Days []time.Weekday
+	// Start and End are the working hours as offsets from midnight.
+	Start time.Duration
+	End   time.Duration
+	// Holidays are dates that are not working days.
+	Holidays []time.Time
+	// Observed are named holidays that are not working days.
+	Observed HolidayProvider
+}
+
+// defaultCalendar works Monday to Friday from 9am to 5pm.
+var defaultCalendar = &WorkWeek{
+	Days: []time.Weekday{
+		time.Monday,
+		time.Tuesday,
+		time.Wednesday,
+		time.Thursday,
+		time.Friday,
+	},
+	Start: 9 * time.Hour,
+	End:   17 * time.Hour,
+}
+
+// Workday reports whether the date of t falls on a working day of the
+// week and is not a holiday.
+func (w *WorkWeek) Workday(t time.Time) bool {
+	if w.Observed != nil && isHoliday(w.Observed, t) {
+		return false
+	}
+	y, M, d := t.Date()
+	for _, h := range w.Holidays {
+		hy, hM, hd := h.Date()
+		if hy == y && hM == M && hd == d {
+			return false
+		}
+	}
+	for _, day := range w.Days {
+		if day == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkingHours returns the start and end of the working hours on the date
+// of t, in the location of t.
+func (w *WorkWeek) WorkingHours(t time.Time) (start, end time.Time) {
+	return w.at(t, w.Start), w.at(t, w.End)
+}
+
+// at returns the wall clock time d after midnight on the date of t.
+func (w *WorkWeek) at(t time.Time, d time.Duration) time.Time {
+	y, M, day := t.Date()
+	h, m, s := int(d/time.Hour), int(d/time.Minute%60), int(d/time.Second%60)
+	return time.Date(y, M, day, h, m, s, int(d%time.Second), t.Location())
+}
+
+// maxIdleDays is the number of consecutive days without working time
+// after which a calendar is assumed to have none.
+const maxIdleDays = 366
+
+var errNoWorkingTime = errors.New("calendar has no working time")
+
+// addBusinessDays returns t moved by n working days of c, keeping the
+// time of day. Counting starts from the day after t, so 1 business day
+// from a Friday is the following Monday.
+func addBusinessDays(c Calendar, t time.Time, n int) (time.Time, error) {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for idle := 0; n > 0; {
+		t = t.AddDate(0, 0, step)
+		if !c.Workday(t) {
+			idle++
+			if idle > maxIdleDays {
+				return time.Time{}, errNoWorkingTime
+			}
+			continue
+		}
+		idle = 0
+		n--
+	}
+	return t, nil
+}
+
+// addWorkingHours returns t moved by n hours of the working time of c.
+// Time outside of working hours is skipped, so 4 working hours after 4pm
+// on a Friday is noon the following Monday.
+func addWorkingHours(c Calendar, t time.Time, n int) (time.Time, error) {
+	d := time.Duration(n) * time.Hour
+	if n < 0 {
+		d = -d
+	}
+	for idle := 0; d > 0; idle++ {
+		if idle > maxIdleDays {
+			return time.Time{}, errNoWorkingTime
+		}
+		start, end := c.WorkingHours(t)
+		y, M, day := t.Date()
+		if n > 0 {
+			if c.Workday(t) && t.Before(end) {
+				if t.Before(start) {
+					t = start
+				}
+				if left := end.Sub(t); d <= left {
+					return t.Add(d), nil
+				}
+				d -= end.Sub(t)
+				idle = 0
+			}
+			t, _ = c.WorkingHours(time.Date(y, M, day+1, 0, 0, 0, 0, t.Location()))
+			continue
+		}
+		if c.Workday(t) && t.After(start) {
+			if t.After(end) {
+				t = end
+			}
+			if left := t.Sub(start); d <= left {
+				return t.Add(-d), nil
+			}
+			d -= t.Sub(start)
+			idle = 0
+		}
+		_, t = c.WorkingHours(time.Date(y, M, day-1, 0, 0, 0, 0, t.Location()))
+	}
+	return t, nil
+}
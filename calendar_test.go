@@ -0,0 +1,167 @@
+package when
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWorkingTime(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	tests := []testcase{
+		{
+			"3 business days from now",
+			time.Date(2006, time.January, 5, 15, 4, 5, 0, loc),
+		},
+		{
+			"5 business days",
+			time.Date(2006, time.January, 9, 15, 4, 5, 0, loc),
+		},
+		{
+			"1 working day ago",
+			time.Date(2005, time.December, 30, 15, 4, 5, 0, loc),
+		},
+		{
+			"two workdays from Saturday",
+			time.Date(2006, time.January, 10, 0, 0, 0, 0, loc),
+		},
+		{
+			"2 business days from Jan 2nd at 3pm",
+			time.Date(2007, time.January, 4, 15, 0, 0, 0, loc),
+		},
+		{
+			"4 working hours after Friday 4pm",
+			time.Date(2006, time.January, 9, 12, 0, 0, 0, loc),
+		},
+		{
+			"8 working hours after Friday 9am",
+			time.Date(2006, time.January, 6, 17, 0, 0, 0, loc),
+		},
+		{
+			"1 business hour from Saturday",
+			time.Date(2006, time.January, 9, 10, 0, 0, 0, loc),
+		},
+		{
+			"3 working hours ago",
+			time.Date(2006, time.January, 2, 12, 4, 5, 0, loc),
+		},
+		{
+			"2 working hours before Monday 10am",
+			time.Date(2006, time.January, 6, 16, 0, 0, 0, loc),
+		},
+		{
+			"tomorrow + 1 business day",
+			time.Date(2006, time.January, 4, 0, 0, 0, 0, loc),
+		},
+		{
+			"1 day 2 business days",
+			time.Date(2006, time.January, 5, 15, 4, 5, 0, loc),
+		},
+	}
+	for _, tc := range tests {
+		tc.apply(t, now)
+	}
+}
+
+func TestParseWorkingTimeCalendar(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	c := &WorkWeek{
+		Days:     []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday},
+		Start:    8*time.Hour + 30*time.Minute,
+		End:      12 * time.Hour,
+		Holidays: []time.Time{time.Date(2006, time.January, 3, 0, 0, 0, 0, time.UTC)},
+	}
+	p := New(WithCalendar(c))
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{
+			"1 business day",
+			time.Date(2006, time.January, 4, 15, 4, 5, 0, loc),
+		},
+		{
+			"3 business days",
+			time.Date(2006, time.January, 9, 15, 4, 5, 0, loc),
+		},
+		{
+			"4 working hours",
+			time.Date(2006, time.January, 5, 9, 0, 0, 0, loc),
+		},
+		{
+			"1 working hour before Wednesday 9am",
+			time.Date(2006, time.January, 2, 11, 30, 0, 0, loc),
+		},
+	}
+	for _, tt := range tests {
+		have, err := p.ParseNow(tt.in, now)
+		if err != nil {
+			t.Fatalf("ParseNow(%q) %v", tt.in, err)
+		}
+		if !have.Equal(tt.want) {
+			t.Errorf("ParseNow(%q)\nhave %v\nwant %v", tt.in, have, tt.want)
+		}
+	}
+}
+
+func TestParseWorkingTimeError(t *testing.T) {
+	tests := []string{
+		"1.5 business days",
+		"half a working hour",
+		"2 working",
+	}
+	for _, tt := range tests {
+		have, err := ParseExpr(tt)
+		if err == nil {
+			t.Errorf("ParseExpr(%q)\nhave %v\nwant parse error", tt, have)
+		}
+	}
+	p := New(WithCalendar(&WorkWeek{Start: 9 * time.Hour, End: 17 * time.Hour}))
+	for _, in := range []string{"1 business day", "1 working hour ago"} {
+		_, err := p.ParseNow(in, time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC))
+		if err != errNoWorkingTime {
+			t.Errorf("ParseNow(%q)\nhave %v\nwant %v", in, err, errNoWorkingTime)
+		}
+	}
+}
+
+func TestWorkWeek(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := &WorkWeek{
+		Days:     []time.Weekday{time.Monday, time.Friday},
+		Start:    9*time.Hour + 30*time.Minute,
+		End:      17 * time.Hour,
+		Holidays: []time.Time{time.Date(2006, time.January, 6, 0, 0, 0, 0, time.UTC)},
+	}
+	tests := []struct {
+		t       time.Time
+		workday bool
+	}{
+		{time.Date(2006, time.January, 2, 23, 0, 0, 0, loc), true},
+		{time.Date(2006, time.January, 3, 12, 0, 0, 0, loc), false},
+		{time.Date(2006, time.January, 6, 12, 0, 0, 0, loc), false},
+		{time.Date(2006, time.January, 13, 12, 0, 0, 0, loc), true},
+	}
+	for _, tt := range tests {
+		if have := c.Workday(tt.t); have != tt.workday {
+			t.Errorf("Workday(%v)\nhave %v\nwant %v", tt.t, have, tt.workday)
+		}
+	}
+	start, end := c.WorkingHours(time.Date(2006, time.January, 2, 23, 0, 0, 0, loc))
+	if want := time.Date(2006, time.January, 2, 9, 30, 0, 0, loc); !start.Equal(want) {
+		t.Errorf("WorkingHours start\nhave %v\nwant %v", start, want)
+	}
+	if want := time.Date(2006, time.January, 2, 17, 0, 0, 0, loc); !end.Equal(want) {
+		t.Errorf("WorkingHours end\nhave %v\nwant %v", end, want)
+	}
+}
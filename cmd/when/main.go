@@ -17,8 +17,9 @@ var (
 	f = flag.String("f", "Mon Jan 2 15:04 MST", "time format layout")
 	l = flag.String("l", "Local", "comma-separated list of time zones to output")
 
-	seconds = flag.Bool("s", false, "output as Unix time in seconds")
-	rfc3339 = flag.Bool("rfc-3339", false, "output as RFC 3339 format")
+	seconds  = flag.Bool("s", false, "output as Unix time in seconds")
+	relative = flag.Bool("r", false, "output in words relative to now")
+	rfc3339  = flag.Bool("rfc-3339", false, "output as RFC 3339 format")
 )
 
 func init() {
@@ -39,6 +40,9 @@ func main() {
 	t, err := when.Parse(expr)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
+		if e, ok := err.(*when.ParseError); ok {
+			fmt.Fprintf(os.Stderr, "%s\n", e.Caret())
+		}
 		os.Exit(1)
 		return
 	}
@@ -46,6 +50,10 @@ func main() {
 		fmt.Println(t.Unix())
 		return
 	}
+	if *relative {
+		fmt.Println(when.Humanize(t, time.Now()))
+		return
+	}
 	timezones := strings.Split(*l, ";")
 	if *u {
 		timezones = []string{"UTC"}
@@ -0,0 +1,457 @@
+package when
+
+import "time"
+
+// env is the environment an expression is evaluated in.
+type env struct {
+	cfg   *Parser
+	input string
+	now   time.Time
+	clock *Clock
+	keep  bool // keep the period containing now, as in "end of March"
+}
+
+// Eval returns the time derived from e relative to now.
+func Eval(e *Expr, now time.Time) (time.Time, error) {
+	return defaultParser.Eval(e, now)
+}
+
+// EvalRange returns the range derived from e relative to now.
+func EvalRange(e *Expr, now time.Time) (Range, error) {
+	return defaultParser.EvalRange(e, now)
+}
+
+// Eval returns the time derived from e relative to now.
+func (p *Parser) Eval(e *Expr, now time.Time) (time.Time, error) {
+	t, _, err := p.anchor(e, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err = p.apply(e, t)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return p.in(t, now), nil
+}
+
+// EvalRange returns the range derived from e relative to now. The range
+// spans the period of the anchor at its granularity, such as the whole
+// day for "tomorrow" or the whole month for "March", shifted by any
+// durations in the expression.
+func (p *Parser) EvalRange(e *Expr, now time.Time) (Range, error) {
+	t, g, err := p.anchor(e, now)
+	if err != nil {
+		return Range{}, err
+	}
+	t = p.truncate(e.Date, g, t)
+	start, err := p.apply(e, t)
+	if err != nil {
+		return Range{}, err
+	}
+	end, err := p.apply(e, g.add(t, 1))
+	if err != nil {
+		return Range{}, err
+	}
+	r := Range{
+		Start:       p.in(start, now),
+		End:         p.in(end, now),
+		Granularity: g,
+	}
+	return r, nil
+}
+
+// truncate returns the start of the period of granularity g containing
+// t, with weeks and years starting as they do for the date d.
+func (p *Parser) truncate(d Date, g Granularity, t time.Time) time.Time {
+	weekStart, yearStart := p.weekStart, time.January
+	switch n := d.(type) {
+	case *WeekDate:
+		weekStart = time.Monday // ISO weeks start on Monday
+	case *PeriodDate:
+		if n.Fiscal {
+			yearStart = p.fiscal
+		}
+	}
+	return g.truncate(t, weekStart, yearStart)
+}
+
+// anchor returns the anchor of e relative to now and its granularity.
+// The anchor is in the location of e if it has one.
+func (p *Parser) anchor(e *Expr, now time.Time) (time.Time, Granularity, error) {
+	if p.loc != nil {
+		now = now.In(p.loc)
+	}
+	if e.Location != nil {
+		now = now.In(e.Location)
+	}
+	if e.IsNow() {
+		return now, Second, nil
+	}
+	env := &env{
+		cfg:   p,
+		input: e.Input,
+		now:   now,
+		clock: e.Clock,
+	}
+	if e.Date == nil {
+		y, M, d := now.Date()
+		return env.at(y, M, d), e.Clock.Precision, nil
+	}
+	t, g, err := e.Date.resolve(env)
+	if err != nil {
+		return time.Time{}, g, err
+	}
+	if e.Clock != nil && e.Clock.Precision > g {
+		g = e.Clock.Precision
+	}
+	return t, g, nil
+}
+
+// in returns t in the location of the derived time.
+func (p *Parser) in(t, now time.Time) time.Time {
+	if p.loc != nil {
+		return t.In(p.loc)
+	}
+	return t.In(now.Location())
+}
+
+// apply applies the offsets following the anchor and then the terms
+// preceding it to t.
+func (p *Parser) apply(e *Expr, t time.Time) (time.Time, error) {
+	var err error
+	for _, o := range e.Offsets {
+		t, err = p.add(t, o.Unit, o.N)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	for _, o := range e.Terms {
+		n := o.N
+		if e.Sub {
+			n = -n
+		}
+		t, err = p.add(t, o.Unit, n)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	return t, nil
+}
+
+// add returns t advanced by n units of g. Business days and working
+// hours are counted on the calendar of the parser.
+func (p *Parser) add(t time.Time, g Granularity, n int) (time.Time, error) {
+	switch g {
+	case BusinessDay:
+		return addBusinessDays(p.calendar, t, n)
+	case WorkingHour:
+		return addWorkingHours(p.calendar, t, n)
+	}
+	return g.add(t, n), nil
+}
+
+// at returns the time at the given date with the clock of the anchor.
+func (e *env) at(y int, M time.Month, d int) time.Time {
+	var h, m, s, ns int
+	if e.clock != nil {
+		h, m, s, ns = e.clock.Hour, e.clock.Minute, e.clock.Second, e.clock.Nanosecond
+	}
+	return time.Date(y, M, d, h, m, s, ns, e.now.Location())
+}
+
+// check returns an error in strict mode if the date of n was normalized.
+func (e *env) check(n Date, ok bool) error {
+	if e.cfg.strict && !ok {
+		return e.error(n, "date out of range")
+	}
+	return nil
+}
+
+// error returns a parse error spanning the date n.
+func (e *env) error(n Date, msg string) *ParseError {
+	s := n.span()
+	err := newParseError(token{pos: s.Pos, end: s.End}, msg)
+	if s.End <= len(e.input) {
+		err.setInput(e.input)
+	}
+	return err
+}
+
+// ahead reports whether the period of granularity g starting at t lies
+// ahead of the reference time, or is still in progress when the period
+// containing the reference time is kept.
+func (e *env) ahead(t time.Time, g Granularity) bool {
+	if e.keep {
+		return g.add(t, 1).After(e.now)
+	}
+	return t.After(e.now)
+}
+
+// resolve steps t, the start of a period of granularity g, by the given
+// years, months and days until it lies on the side of the reference time
+// favored by the bias.
+func (e *env) resolve(t time.Time, g Granularity, years, months, days int) time.Time {
+	if e.cfg.bias == Past {
+		if !t.Before(e.now) {
+			return t.AddDate(-years, -months, -days)
+		}
+		return t
+	}
+	if !e.ahead(t, g) {
+		return t.AddDate(years, months, days)
+	}
+	return t
+}
+
+// biased reports whether d is resolved in the direction of the bias,
+// rather than fixed by its year or relative to the reference time.
+func biased(d Date) bool {
+	switch d := d.(type) {
+	case *MonthDate:
+		return d.Qualifier == Nearest
+	case *WeekdayDate:
+		return d.Qualifier == Nearest
+	case *DayOfMonth:
+		return d.Month.Month != 0 || !d.Month.Relative
+	case *NthWeekday:
+		return d.Month.Month != 0 || !d.Month.Relative
+	case *HolidayDate:
+		return d.Year == 0
+	case *PeriodDate:
+		return !d.Relative && d.Year == 0
+	case *BoundaryDate:
+		return biased(d.Date)
+	}
+	return false
+}
+
+// resolveFn returns fn(0), the start of a period of granularity g, if it
+// lies on the side of the reference time favored by the bias, or else
+// the occurrence one step over.
+func (e *env) resolveFn(g Granularity, fn func(n int) time.Time) time.Time {
+	t := fn(0)
+	if e.cfg.bias == Past {
+		if !t.Before(e.now) {
+			return fn(-1)
+		}
+		return t
+	}
+	if !e.ahead(t, g) {
+		return fn(1)
+	}
+	return t
+}
+
+// month returns the first day of the month identified by r, offset by n
+// steps in the direction r is resolved in.
+func (e *env) month(r MonthRef, n int) time.Time {
+	y, M, _ := e.now.Date()
+	switch {
+	case r.Month != 0:
+		return e.at(y+n, r.Month, 1)
+	case r.Relative:
+		return e.at(y, M+time.Month(r.Offset), 1)
+	}
+	return e.at(y, M+time.Month(n), 1)
+}
+
+// inMonth returns the date computed by fn within the month identified by
+// r, resolving named and unspecified months by the bias.
+func (e *env) inMonth(r MonthRef, fn func(first time.Time) time.Time) time.Time {
+	if r.Month == 0 && r.Relative {
+		return fn(e.month(r, 0))
+	}
+	return e.resolveFn(Day, func(n int) time.Time {
+		return fn(e.month(r, n))
+	})
+}
+
+func (n *RelativeDay) resolve(e *env) (time.Time, Granularity, error) {
+	y, M, d := e.now.Date()
+	return e.at(y, M, d+n.Days), Day, nil
+}
+
+func (n *CalendarDate) resolve(e *env) (time.Time, Granularity, error) {
+	switch {
+	case n.Month == 0:
+		return e.at(n.Year, time.January, 1), Year, nil
+	case n.Day == 0:
+		t := e.at(n.Year, n.Month, 1)
+		return t, Month, e.check(n, t.Month() == n.Month)
+	}
+	t := e.at(n.Year, n.Month, n.Day)
+	return t, Day, e.check(n, t.Month() == n.Month && t.Day() == n.Day)
+}
+
+func (n *WeekDate) resolve(e *env) (time.Time, Granularity, error) {
+	jan4 := e.at(n.Year, time.January, 4)
+	days := 7*(n.Week-1) - (int(jan4.Weekday())+6)%7
+	if n.Day == 0 {
+		return jan4.AddDate(0, 0, days), Week, nil
+	}
+	return jan4.AddDate(0, 0, days+n.Day-1), Day, nil
+}
+
+func (n *MonthDate) resolve(e *env) (time.Time, Granularity, error) {
+	y := e.now.Year()
+	switch n.Qualifier {
+	case Next:
+		if e.cfg.next == NextOccurrence && n.Month > e.now.Month() {
+			return e.at(y, n.Month, 1), Month, nil
+		}
+		return e.at(y+1, n.Month, 1), Month, nil
+	case Last:
+		if n.Month >= e.now.Month() {
+			y--
+		}
+		return e.at(y, n.Month, 1), Month, nil
+	case Upcoming:
+		if n.Month <= e.now.Month() {
+			y++
+		}
+		return e.at(y, n.Month, 1), Month, nil
+	case This:
+		return e.at(y, n.Month, 1), Month, nil
+	}
+	t := e.at(y, n.Month, 1)
+	return e.resolve(t, Month, 1, 0, 0), Month, nil
+}
+
+func (n *DayOfMonth) resolve(e *env) (time.Time, Granularity, error) {
+	t := e.inMonth(n.Month, func(first time.Time) time.Time {
+		if n.Day < 0 {
+			return first.AddDate(0, 1, n.Day)
+		}
+		return first.AddDate(0, 0, n.Day-1)
+	})
+	return t, Day, e.check(n, n.Day < 0 || t.Day() == n.Day)
+}
+
+func (n *WeekdayDate) resolve(e *env) (time.Time, Granularity, error) {
+	y, M, d := e.now.Date()
+	today := e.at(y, M, d)
+	days := int(n.Weekday - today.Weekday())
+	switch n.Qualifier {
+	case Next:
+		if e.cfg.next == NextOccurrence {
+			return e.upcoming(today, n.Weekday), Day, nil
+		}
+		start := e.cfg.weekStart
+		days = 7 - int(today.Weekday()-start+7)%7 + int(n.Weekday-start+7)%7
+	case Last:
+		if days >= 0 {
+			days -= 7
+		}
+	case Upcoming:
+		return e.upcoming(today, n.Weekday), Day, nil
+	case This:
+		start := e.cfg.weekStart
+		days = int(n.Weekday-start+7)%7 - int(today.Weekday()-start+7)%7
+	default:
+		switch {
+		case e.cfg.bias == Past && days >= 0:
+			days -= 7
+		case e.cfg.bias != Past && (days < 0 || days == 0 && !e.keep):
+			days += 7
+		}
+	}
+	return today.AddDate(0, 0, days), Day, nil
+}
+
+// upcoming returns the first weekday w after t.
+func (e *env) upcoming(t time.Time, w time.Weekday) time.Time {
+	days := int(w - t.Weekday())
+	if days <= 0 {
+		days += 7
+	}
+	return t.AddDate(0, 0, days)
+}
+
+func (n *NthWeekday) resolve(e *env) (time.Time, Granularity, error) {
+	t := e.inMonth(n.Month, func(first time.Time) time.Time {
+		if n.N < 0 {
+			return nthLastWeekday(first, -n.N, n.Weekday)
+		}
+		return nthWeekday(first, n.N, n.Weekday)
+	})
+	return t, Day, nil
+}
+
+func (n *HolidayDate) resolve(e *env) (time.Time, Granularity, error) {
+	date := func(y int) (time.Time, bool) {
+		if e.cfg.holidays == nil {
+			return time.Time{}, false
+		}
+		M, d, ok := e.cfg.holidays.Date(n.Name, y)
+		return e.at(y, M, d), ok
+	}
+	if n.Year != 0 {
+		t, ok := date(n.Year)
+		if !ok {
+			return t, Day, e.error(n, "unknown holiday")
+		}
+		return t, Day, nil
+	}
+	if _, ok := date(e.now.Year()); !ok {
+		return time.Time{}, Day, e.error(n, "unknown holiday")
+	}
+	t := e.resolveFn(Day, func(i int) time.Time {
+		t, _ := date(e.now.Year() + i)
+		return t
+	})
+	return t, Day, nil
+}
+
+func (n *PeriodDate) resolve(e *env) (time.Time, Granularity, error) {
+	start := time.January
+	if n.Fiscal {
+		start = e.cfg.fiscal
+	}
+	if n.Relative {
+		t := n.Unit.truncate(e.now, e.cfg.weekStart, start)
+		if n.Unit > Day {
+			return n.Unit.add(t, n.Offset), n.Unit, nil
+		}
+		return n.Unit.add(e.at(t.Date()), n.Offset), n.Unit, nil
+	}
+	period := func(y int) time.Time {
+		if start != time.January {
+			y-- // named by the year it ends in
+		}
+		t := e.at(y, start, 1)
+		if n.N > 0 {
+			t = n.Unit.add(t, n.N-1)
+		}
+		return t
+	}
+	if n.Year != 0 {
+		return period(n.Year), n.Unit, nil
+	}
+	y := e.now.Year()
+	if start != time.January && e.now.Month() >= start {
+		y++
+	}
+	t := e.resolveFn(n.Unit, func(i int) time.Time {
+		return period(y + i)
+	})
+	return t, n.Unit, nil
+}
+
+func (n *BoundaryDate) resolve(e *env) (time.Time, Granularity, error) {
+	// The period containing the reference time is kept, so "end of March"
+	// is this March until it is over.
+	inner := *e
+	inner.keep = true
+	t, g, err := n.Date.resolve(&inner)
+	if err != nil {
+		return t, g, err
+	}
+	t = e.cfg.truncate(n.Date, g, t)
+	if n.End {
+		t = g.add(t, 1).Add(-1)
+	}
+	if e.clock != nil {
+		t = e.at(t.Date())
+	}
+	return t, Day, nil
+}
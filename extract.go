@@ -0,0 +1,177 @@
+package when
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Match is an expression found within text by Extract.
+type Match struct {
+	Pos  int    // byte offset of the expression within the text
+	End  int    // byte offset just past the expression
+	Text string // the text of the expression
+	Time time.Time
+	Expr *Expr
+}
+
+// Extract returns the expressions found within text and the times they
+// derive relative to now.
+func Extract(text string, now time.Time) []Match {
+	return defaultParser.Extract(text, now)
+}
+
+// Extract returns the expressions found within text and the times they
+// derive relative to now, as in "tomorrow at 3pm" within "let's meet
+// tomorrow at 3pm to review". Words the parser does not know and marks
+// of punctuation separate expressions rather than failing. The longest
+// expression of up to 16 tokens starting at each word is matched. A
+// number without a unit, month or other word naming a time is not
+// matched, so "I have 5 apples" has no matches, and neither is a month
+// or weekday that is also a common word without a number, qualifier or
+// capital letter, as in "we may go".
+func (p *Parser) Extract(text string, now time.Time) []Match {
+	l := p.lexer(text)
+	l.skip = true
+	for state := readExpr; state != nil; {
+		state = state(l)
+	}
+	var matches []Match
+	start := 0
+	for _, gap := range append(l.gaps, len(l.tokens)) {
+		matches = append(matches, p.extract(text, l.tokens[start:gap], now)...)
+		start = gap
+	}
+	return matches
+}
+
+// maxMatchTokens is the most tokens of an expression matched by Extract,
+// which bounds the work of matching a long run of known words.
+const maxMatchTokens = 16
+
+// extract returns the expressions found within the run of tokens read
+// from text without skipping any word.
+func (p *Parser) extract(text string, tokens []token, now time.Time) []Match {
+	var matches []Match
+	for i := 0; i < len(tokens); i++ {
+		j := len(tokens)
+		if j > i+maxMatchTokens {
+			j = i + maxMatchTokens
+		}
+		for ; j > i; j-- {
+			m, ok := p.match(text, tokens[i:j], now)
+			if ok {
+				matches = append(matches, m)
+				i = j - 1
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// match returns the expression parsed from tokens, reporting whether
+// they form an expression naming a time.
+func (p *Parser) match(text string, tokens []token, now time.Time) (Match, bool) {
+	if !namesTime(text, tokens) {
+		return Match{}, false
+	}
+	pos, end := tokens[0].pos, tokens[len(tokens)-1].end
+	e, err := parseTokens(tokens, end, p)
+	if err != nil {
+		return Match{}, false
+	}
+	e.Input = text
+	t, err := p.Eval(e, now)
+	if err != nil {
+		return Match{}, false
+	}
+	m := Match{
+		Pos:  pos,
+		End:  end,
+		Text: text[pos:end],
+		Time: t,
+		Expr: e,
+	}
+	return m, true
+}
+
+// namesTime reports whether tokens read from text contain a word naming
+// a time, rather than only numbers and keywords that are common in any
+// text. A month or weekday that is also a common word, as in "we may go"
+// or "sun is out", names a time only with a number, a qualifier such as
+// "next", or a capital letter that does not start a sentence. A unit
+// following "the", as in "the day", names a time only when qualified.
+func namesTime(text string, tokens []token) bool {
+	for i, t := range tokens {
+		switch t.typ {
+		case tokenKeyword:
+			switch t.val {
+			case "eod", "eow", "eom", "eoq", "eoy":
+				return true
+			}
+		case tokenDigit, tokenOperatorAdd, tokenOperatorSub, tokenFrom,
+			tokenBefore, tokenAgo, tokenZone:
+		case tokenMonth, tokenWeekday:
+			if !ambiguous(strings.ToLower(text[t.pos:t.end])) || inContext(text, tokens, i) {
+				return true
+			}
+		case tokenUnit:
+			if !article(tokens, i) {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// article reports whether the unit tokens[i] follows "the" without a
+// quantity and is not qualified by "after" or "before", as in "the day"
+// but not "the week after next".
+func article(tokens []token, i int) bool {
+	if i == 0 || tokens[i-1].typ != tokenKeyword || tokens[i-1].val != "the" {
+		return false
+	}
+	if i+1 < len(tokens) {
+		switch tokens[i+1].typ {
+		case tokenFrom, tokenBefore:
+			return false
+		}
+	}
+	return true
+}
+
+// ambiguous reports whether the month or weekday written as v is also a
+// common word. Abbreviations are ambiguous, as are "may", "march" and "august".
+func ambiguous(v string) bool {
+	switch v {
+	case "may", "march", "august":
+		return true
+	}
+	return len(v) == 3
+}
+
+// inContext reports whether the month or weekday tokens[i] read from
+// text is named by a number or qualifier within tokens, or is
+// capitalized other than at the start of a sentence.
+func inContext(text string, tokens []token, i int) bool {
+	for _, t := range tokens {
+		if t.typ == tokenDigit || t.typ == tokenOrdinal {
+			return true
+		}
+	}
+	if i > 0 && tokens[i-1].typ == tokenKeyword {
+		switch tokens[i-1].val {
+		case "next", "last", "this", "upcoming", "coming", "previous":
+			return true
+		}
+	}
+	pos := tokens[i].pos
+	if !unicode.IsUpper(rune(text[pos])) {
+		return false
+	}
+	before := strings.TrimRightFunc(text[:pos], unicode.IsSpace)
+	return before != "" && !strings.ContainsAny(before[len(before)-1:], ".!?")
+}
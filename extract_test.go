@@ -0,0 +1,176 @@
+package when
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExtract(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	type match struct {
+		pos  int
+		text string
+		want time.Time
+	}
+	tests := []struct {
+		in   string
+		want []match
+	}{
+		{
+			"let's meet tomorrow at 3pm to review",
+			[]match{
+				{11, "tomorrow at 3pm", time.Date(2006, time.January, 3, 15, 0, 0, 0, loc)},
+			},
+		},
+		{
+			"remind me in 2 hours about the deploy",
+			[]match{
+				{10, "in 2 hours", time.Date(2006, time.January, 2, 17, 4, 5, 0, loc)},
+			},
+		},
+		{
+			"see you on Friday.",
+			[]match{
+				{8, "on Friday", time.Date(2006, time.January, 6, 0, 0, 0, 0, loc)},
+			},
+		},
+		{
+			"the report is due March 14th, or 2 days after",
+			[]match{
+				{18, "March 14th", time.Date(2006, time.March, 14, 0, 0, 0, 0, loc)},
+				{33, "2 days", time.Date(2006, time.January, 4, 15, 4, 5, 0, loc)},
+			},
+		},
+		{
+			"ship it by EOD and call me at 5pm!",
+			[]match{
+				{11, "EOD", time.Date(2006, time.January, 2, 23, 59, 59, 999999999, loc)},
+				{27, "at 5pm", time.Date(2006, time.January, 2, 17, 0, 0, 0, loc)},
+			},
+		},
+		{
+			"we met a few days ago",
+			[]match{
+				{7, "a few days ago", time.Date(2005, time.December, 30, 15, 4, 5, 0, loc)},
+			},
+		},
+		{
+			"born on 01/02/2006 at noon",
+			[]match{
+				{5, "on 01/02/2006 at noon", time.Date(2006, time.January, 2, 12, 0, 0, 0, loc)},
+			},
+		},
+		{
+			"see you tomorrow.",
+			[]match{
+				{8, "tomorrow", time.Date(2006, time.January, 3, 0, 0, 0, 0, loc)},
+			},
+		},
+		{
+			"Today works (or tomorrow)",
+			[]match{
+				{0, "Today", time.Date(2006, time.January, 2, 0, 0, 0, 0, loc)},
+				{16, "tomorrow", time.Date(2006, time.January, 3, 0, 0, 0, 0, loc)},
+			},
+		},
+		{
+			"see you in May",
+			[]match{
+				{11, "May", time.Date(2006, time.May, 1, 0, 0, 0, 0, loc)},
+			},
+		},
+		{
+			"we may go on may 5th or next sat",
+			[]match{
+				{10, "on may 5th", time.Date(2006, time.May, 5, 0, 0, 0, 0, loc)},
+				{24, "next sat", time.Date(2006, time.January, 14, 0, 0, 0, 0, loc)},
+			},
+		},
+		{
+			"I have 5 apples",
+			nil,
+		},
+		{
+			"we may go",
+			nil,
+		},
+		{
+			"I will march on",
+			nil,
+		},
+		{
+			"sun is out",
+			nil,
+		},
+		{
+			"May I help?",
+			nil,
+		},
+		{
+			"I spent the day at the beach",
+			nil,
+		},
+		{
+			"The second test passed",
+			nil,
+		},
+		{
+			"see you the week after next",
+			[]match{
+				{8, "the week after next", time.Date(2006, time.January, 15, 0, 0, 0, 0, loc)},
+			},
+		},
+		{
+			"",
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		have := Extract(tt.in, now)
+		if len(have) != len(tt.want) {
+			t.Errorf("Extract(%q) have %d matches, want %d\n%v", tt.in, len(have), len(tt.want), have)
+			continue
+		}
+		for i, want := range tt.want {
+			m := have[i]
+			if m.Pos != want.pos || m.End != want.pos+len(want.text) || m.Text != want.text || !m.Time.Equal(want.want) {
+				t.Errorf("Extract(%q) match %d\nhave %d %q %v\nwant %d %q %v", tt.in, i, m.Pos, m.Text, m.Time, want.pos, want.text, want.want)
+			}
+		}
+	}
+}
+
+func TestExtractLocale(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	p := New(WithLocale(Spanish))
+	have := p.Extract("nos vemos mañana a las 3pm en la oficina", now)
+	if len(have) != 1 {
+		t.Fatalf("Extract have %d matches, want 1\n%v", len(have), have)
+	}
+	want := time.Date(2006, time.January, 3, 15, 0, 0, 0, loc)
+	if have[0].Text != "mañana a las 3pm" || !have[0].Time.Equal(want) {
+		t.Errorf("Extract\nhave %q %v\nwant %q %v", have[0].Text, have[0].Time, "mañana a las 3pm", want)
+	}
+}
+
+func TestExtractLongText(t *testing.T) {
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	text := strings.Repeat("next week at noon on the ", 400)
+	have := Extract(text, now)
+	if len(have) == 0 {
+		t.Fatalf("Extract have no matches")
+	}
+	want := time.Date(2006, time.January, 8, 12, 0, 0, 0, time.UTC)
+	if have[0].Text != "next week at noon" || !have[0].Time.Equal(want) {
+		t.Errorf("Extract\nhave %q %v\nwant %q %v", have[0].Text, have[0].Time, "next week at noon", want)
+	}
+}
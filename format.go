@@ -0,0 +1,274 @@
+package when
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format returns the canonical text of an expression returned by
+// ParseExpr. The text parses to an expression that evaluates to the same
+// time, so "1y 2M and 3w & 4d" is formatted as "1 year 2 months 3 weeks
+// 4 days" and "quarter past 3 o'clock in the afternoon" as "3:15pm".
+func Format(e *Expr) string {
+	var s []string
+	if len(e.Terms) > 0 {
+		s = append(s, formatTerms(e.Terms))
+	}
+	offsets := e.Offsets
+	if d, ok := e.Date.(*RelativeDay); ok && (d.Days < -1 || d.Days > 1) {
+		// Only today, tomorrow and yesterday have names.
+		offsets = append([]Term{{d.Days, Day}}, offsets...)
+	}
+	if e.IsNow() && len(offsets) == 0 {
+		switch {
+		case len(e.Terms) == 0:
+			return "now"
+		case e.Sub:
+			s = append(s, "ago")
+		}
+		return strings.Join(s, " ")
+	}
+	if len(e.Terms) > 0 {
+		if e.Sub {
+			s = append(s, "before")
+		} else {
+			s = append(s, "from")
+		}
+	}
+	switch {
+	case e.IsNow():
+		s = append(s, "now")
+	case e.Date == nil:
+		s = append(s, formatClock(e.Clock))
+	default:
+		s = append(s, formatDate(e.Date))
+		if e.Clock != nil {
+			s = append(s, "at", formatClock(e.Clock))
+		}
+	}
+	if e.Location != nil && !e.IsNow() {
+		s = append(s, e.Location.String())
+	}
+	for _, t := range offsets {
+		if t.N < 0 {
+			s = append(s, "-", formatTerm(Term{-t.N, t.Unit}))
+		} else {
+			s = append(s, "+", formatTerm(t))
+		}
+	}
+	return strings.Join(s, " ")
+}
+
+// String returns the canonical text of the expression.
+func (e *Expr) String() string {
+	return Format(e)
+}
+
+func formatTerms(terms []Term) string {
+	s := formatTerm(terms[0])
+	for _, t := range terms[1:] {
+		if t.N < 0 {
+			s += " - " + formatTerm(Term{-t.N, t.Unit})
+		} else {
+			s += " " + formatTerm(t)
+		}
+	}
+	return s
+}
+
+func formatTerm(t Term) string {
+	s := strconv.Itoa(t.N) + " " + t.Unit.String()
+	if t.N != 1 {
+		s += "s"
+	}
+	return s
+}
+
+func formatClock(c *Clock) string {
+	g := c.Precision
+	switch {
+	case c.Nanosecond%int(time.Microsecond) != 0:
+		g = Nanosecond
+	case c.Nanosecond%int(time.Millisecond) != 0 && g < Microsecond:
+		g = Microsecond
+	case c.Nanosecond != 0 && g < Millisecond:
+		g = Millisecond
+	case c.Second != 0 && g < Second:
+		g = Second
+	case c.Minute != 0 && g < Minute:
+		g = Minute
+	}
+	h := c.Hour % 12
+	if h == 0 {
+		h = 12
+	}
+	m := "am"
+	if c.Hour >= 12 {
+		m = "pm"
+	}
+	switch {
+	case g > Second:
+		// The fraction is written to the precision of the clock.
+		n := 3 * int(g-Second)
+		f := fmt.Sprintf("%09d", c.Nanosecond)[:n]
+		return fmt.Sprintf("%02d:%02d:%02d.%s", c.Hour, c.Minute, c.Second, f)
+	case g == Second:
+		return fmt.Sprintf("%02d:%02d:%02d", c.Hour, c.Minute, c.Second)
+	case g == Minute:
+		return fmt.Sprintf("%d:%02d%s", h, c.Minute, m)
+	case c.Hour == 0:
+		return "midnight"
+	case c.Hour == 12:
+		return "noon"
+	}
+	return strconv.Itoa(h) + m
+}
+
+func formatDate(d Date) string {
+	switch d := d.(type) {
+	case *RelativeDay:
+		switch d.Days {
+		case 1:
+			return "tomorrow"
+		case -1:
+			return "yesterday"
+		}
+		return "today"
+	case *CalendarDate:
+		switch {
+		case d.Month == 0:
+			return strconv.Itoa(d.Year)
+		case d.Day == 0:
+			return fmt.Sprintf("%d-%02d", d.Year, int(d.Month))
+		}
+		return fmt.Sprintf("%d-%02d-%02d", d.Year, int(d.Month), d.Day)
+	case *WeekDate:
+		if d.Day == 0 {
+			return fmt.Sprintf("%d-W%02d", d.Year, d.Week)
+		}
+		return fmt.Sprintf("%d-W%02d-%d", d.Year, d.Week, d.Day)
+	case *MonthDate:
+		return formatQualifier(d.Qualifier) + d.Month.String()
+	case *DayOfMonth:
+		switch {
+		case d.Day < 0:
+			return "the " + formatLast(-d.Day) + " day of " + formatMonthRef(d.Month)
+		case d.Month.Month != 0:
+			return d.Month.Month.String() + " " + ordinal(d.Day)
+		case d.Month.Relative:
+			return "the " + ordinal(d.Day) + " of " + formatMonthRef(d.Month)
+		}
+		return "the " + ordinal(d.Day)
+	case *WeekdayDate:
+		return formatQualifier(d.Qualifier) + d.Weekday.String()
+	case *HolidayDate:
+		if d.Year != 0 {
+			return d.Name + " " + strconv.Itoa(d.Year)
+		}
+		return d.Name
+	case *PeriodDate:
+		return formatPeriod(d)
+	case *BoundaryDate:
+		s := "start of "
+		if d.End {
+			s = "end of "
+		}
+		if n, ok := d.Date.(*PeriodDate); ok && n.Relative && n.Offset == 0 {
+			// "end of the month" rather than "end of this month".
+			return s + "the " + n.Unit.String()
+		}
+		return s + formatDate(d.Date)
+	case *NthWeekday:
+		n := ordinal(d.N)
+		if d.N < 0 {
+			n = formatLast(-d.N)
+		}
+		return "the " + n + " " + d.Weekday.String() + " of " + formatMonthRef(d.Month)
+	}
+	return ""
+}
+
+// formatQualifier returns the word qualifying a month or weekday,
+// followed by a space, or nothing for the nearest occurrence.
+func formatQualifier(q Qualifier) string {
+	switch q {
+	case Next:
+		return "next "
+	case Last:
+		return "last "
+	case Upcoming:
+		return "upcoming "
+	case This:
+		return "this "
+	}
+	return ""
+}
+
+func formatPeriod(d *PeriodDate) string {
+	if d.Relative {
+		u := d.Unit.String()
+		switch {
+		case d.Offset < -1:
+			return "the " + u + " before last"
+		case d.Offset < 0:
+			return "last " + u
+		case d.Offset > 1:
+			return "the " + u + " after next"
+		case d.Offset > 0:
+			return "next " + u
+		}
+		return "this " + u
+	}
+	var s []string
+	switch d.Unit {
+	case Quarter:
+		s = append(s, "Q"+strconv.Itoa(d.N))
+	case Half:
+		s = append(s, "H"+strconv.Itoa(d.N))
+	}
+	switch {
+	case d.Fiscal:
+		s = append(s, "FY"+strconv.Itoa(d.Year))
+	case d.Year != 0:
+		s = append(s, strconv.Itoa(d.Year))
+	}
+	return strings.Join(s, " ")
+}
+
+// formatLast returns the nth last ordinal, as in "last" or "2nd last".
+func formatLast(n int) string {
+	if n == 1 {
+		return "last"
+	}
+	return ordinal(n) + " last"
+}
+
+func formatMonthRef(r MonthRef) string {
+	switch {
+	case r.Month != 0:
+		return r.Month.String()
+	case r.Offset < 0:
+		return "last month"
+	case r.Offset > 0:
+		return "next month"
+	}
+	return "the month"
+}
+
+// ordinal returns n with its English ordinal suffix.
+func ordinal(n int) string {
+	s := strconv.Itoa(n)
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+		return s + "th"
+	case n%10 == 1:
+		return s + "st"
+	case n%10 == 2:
+		return s + "nd"
+	case n%10 == 3:
+		return s + "rd"
+	}
+	return s + "th"
+}
@@ -0,0 +1,119 @@
+package when
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormat(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", "now"},
+		{"now - 2h", "now - 2 hours"},
+		{"1y 2M and 3w & 4d", "1 year 2 months 3 weeks 4 days"},
+		{"1 year - 1 month from now", "1 year - 1 month"},
+		{"6 hours ago", "6 hours ago"},
+		{"1 minute before now + 1 day", "1 minute before now + 1 day"},
+		{"quarter past 3 o'clock in the afternoon", "3:15pm"},
+		{"half past 11pm", "11:30pm"},
+		{"15:04:05", "15:04:05"},
+		{"4:00PM", "4:00pm"},
+		{"at 12am", "midnight"},
+		{"noon tomorrow", "tomorrow at noon"},
+		{"2 days before yesterday", "2 days before yesterday"},
+		{"2006", "2006"},
+		{"2006-1-2 3pm", "2006-01-02 at 3pm"},
+		{"4pm 2006-02", "2006-02 at 4pm"},
+		{"next mar", "next March"},
+		{"3pm march", "March at 3pm"},
+		{"on the 4th", "the 4th"},
+		{"4th of next month", "the 4th of next month"},
+		{"the 14th March", "March 14th"},
+		{"on the last day of the month", "the last day of the month"},
+		{"2nd last day of february at noon", "the 2nd last day of February at noon"},
+		{"fri", "Friday"},
+		{"upcoming fri + 1w", "upcoming Friday + 1 week"},
+		{"last mon", "last Monday"},
+		{"1st Tuesday in last month", "the 1st Tuesday of last month"},
+		{"3rd last sun of april", "the 3rd last Sunday of April"},
+		{"2 weeks from the 22nd", "2 weeks from the 22nd"},
+		{"the 11th + 1 day", "the 11th + 1 day"},
+		{"2006 at 3pm + 1 day", "2006 at 3pm + 1 day"},
+		{"2006-01-02T15:04:05Z", "2006-01-02 at 15:04:05 UTC"},
+		{"2006-01-02T15:04:05.120-07:00", "2006-01-02 at 15:04:05.120 -07:00"},
+		{"15:04:05.250 + 500ms", "15:04:05.250 + 500 milliseconds"},
+		{"12:00:00.000001", "12:00:00.000001"},
+		{"20060102T1504 + 1h", "2006-01-02 at 3:04pm + 1 hour"},
+		{"2006-W01-2", "2006-W01-2"},
+		{"2006w01", "2006-W01"},
+		{"2006-032", "2006-02-01"},
+		{"P1Y2M3DT4H5M6S from now", "1 year 2 months 3 days 4 hours 5 minutes 6 seconds"},
+		{"tomorrow - PT1H30M", "tomorrow - 1 hour - 30 minutes"},
+		{"1.5 months ago", "1 month 15 days ago"},
+		{"a quarter of an hour before noon + 2.5h", "15 minutes before noon + 2 hours + 30 minutes"},
+		{"3 workdays ago", "3 business days ago"},
+		{"4 business hours after fri 4pm", "4 working hours from Friday at 4pm"},
+		{"jan 2nd at 3pm + 1 working day", "January 2nd at 3pm + 1 business day"},
+		{"christmas", "Christmas"},
+		{"the day after thanksgiving 2025", "1 day from Thanksgiving 2025"},
+		{"new years eve 11pm", "New Year's Eve at 11pm"},
+		{"q3", "Q3"},
+		{"next quarter + 1 week", "next quarter + 1 week"},
+		{"FY26 Q1", "Q1 FY2026"},
+		{"h2 2025 at noon", "H2 2025 at noon"},
+		{"EOD", "end of the day"},
+		{"end of month", "end of the month"},
+		{"start of next week", "start of next week"},
+		{"beginning of the year", "start of the year"},
+		{"end of q2 2025 at 5pm", "end of Q2 2025 at 5pm"},
+		{"2 days before eom", "2 days before end of the month"},
+		{"this friday", "this Friday"},
+		{"previous march", "last March"},
+		{"coming friday at 3pm", "upcoming Friday at 3pm"},
+		{"this week", "this week"},
+		{"the week after next", "the week after next"},
+		{"the day before last + 2h", "the day before last + 2 hours"},
+		{"past hour", "last hour"},
+		{"in 1 day and 2 hours", "1 day 2 hours"},
+	}
+	for _, tt := range tests {
+		e, err := ParseExpr(tt.in)
+		if err != nil {
+			t.Errorf("ParseExpr(%q)\nunexpected error: %v", tt.in, err)
+			continue
+		}
+		have := Format(e)
+		if have != tt.want {
+			t.Errorf("Format(%q)\nhave %q\nwant %q", tt.in, have, tt.want)
+			continue
+		}
+		a, err := ParseNow(tt.in, now)
+		if err != nil {
+			t.Errorf("ParseNow(%q)\nunexpected error: %v", tt.in, err)
+			continue
+		}
+		b, err := ParseNow(have, now)
+		if err != nil {
+			t.Errorf("ParseNow(%q)\nunexpected error: %v", have, err)
+			continue
+		}
+		if !a.Equal(b) {
+			t.Errorf("ParseNow(%q)\nhave %v\nwant %v", have, b, a)
+		}
+		e, err = ParseExpr(have)
+		if err != nil {
+			t.Errorf("ParseExpr(%q)\nunexpected error: %v", have, err)
+			continue
+		}
+		if again := Format(e); again != have {
+			t.Errorf("Format(%q)\nhave %q\nwant %q", have, again, have)
+		}
+	}
+}
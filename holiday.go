@@ -0,0 +1,318 @@
+package when
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// HolidayProvider provides the dates of named holidays. Names are
+// matched without regard to case, apostrophes or repeated spaces, so
+// "New Year's Day" also matches "new years day".
+type HolidayProvider interface {
+	// Names returns the names of the holidays provided.
+	Names() []string
+	// Date returns the month and day of the named holiday in year y, or
+	// false if the holiday is not provided.
+	Date(name string, y int) (time.Month, int, bool)
+}
+
+// FixedHoliday is a holiday on the same date every year.
+type FixedHoliday struct {
+	Name  string
+	Month time.Month
+	Day   int
+}
+
+// Names returns the name of the holiday.
+func (h FixedHoliday) Names() []string {
+	return []string{h.Name}
+}
+
+// Date returns the date of the holiday.
+func (h FixedHoliday) Date(name string, y int) (time.Month, int, bool) {
+	if holidayKey(name) != holidayKey(h.Name) {
+		return 0, 0, false
+	}
+	return h.Month, h.Day, true
+}
+
+// WeekdayHoliday is a holiday on the nth weekday of a month, or the nth
+// last weekday if N is negative.
+type WeekdayHoliday struct {
+	Name    string
+	Month   time.Month
+	Weekday time.Weekday
+	N       int
+}
+
+// Names returns the name of the holiday.
+func (h WeekdayHoliday) Names() []string {
+	return []string{h.Name}
+}
+
+// Date returns the date of the holiday in year y.
+func (h WeekdayHoliday) Date(name string, y int) (time.Month, int, bool) {
+	if holidayKey(name) != holidayKey(h.Name) {
+		return 0, 0, false
+	}
+	first := time.Date(y, h.Month, 1, 0, 0, 0, 0, time.UTC)
+	t := nthWeekday(first, h.N, h.Weekday)
+	if h.N < 0 {
+		t = nthLastWeekday(first, -h.N, h.Weekday)
+	}
+	return t.Month(), t.Day(), true
+}
+
+// EasterHoliday is a holiday the given number of days after Western
+// Easter Sunday, or before it if Days is negative.
+type EasterHoliday struct {
+	Name string
+	Days int
+}
+
+// Names returns the name of the holiday.
+func (h EasterHoliday) Names() []string {
+	return []string{h.Name}
+}
+
+// Date returns the date of the holiday in year y.
+func (h EasterHoliday) Date(name string, y int) (time.Month, int, bool) {
+	if holidayKey(name) != holidayKey(h.Name) {
+		return 0, 0, false
+	}
+	M, d := easter(y)
+	t := time.Date(y, M, d+h.Days, 0, 0, 0, 0, time.UTC)
+	return t.Month(), t.Day(), true
+}
+
+// easter returns the date of Easter Sunday in the Gregorian calendar,
+// computed with the anonymous Gregorian algorithm.
+func easter(y int) (time.Month, int) {
+	a := y % 19
+	b, c := y/100, y%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	n := h + l - 7*m + 114
+	return time.Month(n / 31), n%31 + 1
+}
+
+// Holidays combines providers. A holiday provided by more than one is
+// dated by the first.
+type Holidays []HolidayProvider
+
+// Names returns the names of the holidays of all providers.
+func (hs Holidays) Names() []string {
+	var names []string
+	for _, h := range hs {
+		names = append(names, h.Names()...)
+	}
+	return names
+}
+
+// Date returns the date of the named holiday from the first provider
+// that provides it.
+func (hs Holidays) Date(name string, y int) (time.Month, int, bool) {
+	for _, h := range hs {
+		if M, d, ok := h.Date(name, y); ok {
+			return M, d, true
+		}
+	}
+	return 0, 0, false
+}
+
+// DefaultHolidays are common holidays of the United States and of the
+// Christian calendar. They are used unless the parser is configured with
+// WithHolidays.
+var DefaultHolidays = Holidays{
+	FixedHoliday{"New Year's Day", time.January, 1},
+	WeekdayHoliday{"Martin Luther King Day", time.January, time.Monday, 3},
+	FixedHoliday{"Valentine's Day", time.February, 14},
+	WeekdayHoliday{"Presidents Day", time.February, time.Monday, 3},
+	FixedHoliday{"St Patrick's Day", time.March, 17},
+	EasterHoliday{"Good Friday", -2},
+	EasterHoliday{"Easter", 0},
+	EasterHoliday{"Easter Sunday", 0},
+	EasterHoliday{"Easter Monday", 1},
+	WeekdayHoliday{"Mother's Day", time.May, time.Sunday, 2},
+	WeekdayHoliday{"Memorial Day", time.May, time.Monday, -1},
+	WeekdayHoliday{"Father's Day", time.June, time.Sunday, 3},
+	FixedHoliday{"Juneteenth", time.June, 19},
+	FixedHoliday{"Independence Day", time.July, 4},
+	WeekdayHoliday{"Labor Day", time.September, time.Monday, 1},
+	WeekdayHoliday{"Columbus Day", time.October, time.Monday, 2},
+	FixedHoliday{"Halloween", time.October, 31},
+	FixedHoliday{"Veterans Day", time.November, 11},
+	WeekdayHoliday{"Thanksgiving", time.November, time.Thursday, 4},
+	WeekdayHoliday{"Thanksgiving Day", time.November, time.Thursday, 4},
+	FixedHoliday{"Christmas Eve", time.December, 24},
+	FixedHoliday{"Christmas", time.December, 25},
+	FixedHoliday{"Christmas Day", time.December, 25},
+	FixedHoliday{"Boxing Day", time.December, 26},
+	FixedHoliday{"New Year's Eve", time.December, 31},
+}
+
+// LoadHolidays reads holidays from r, one per line, written as the name
+// of the holiday, a colon and the date it falls on:
+//
+//	# Comments and blank lines are ignored.
+//	Founders Day: March 3rd
+//	Harvest Festival: the 2nd Monday of October
+//	Arbor Day: the last Friday of April
+//	Ascension Day: 39 days after Easter
+//
+// Dates are a month and day, the nth or nth last weekday of a month, or
+// a number of days before or after Easter.
+func LoadHolidays(r io.Reader) (Holidays, error) {
+	var hs Holidays
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, rule, ok := strings.Cut(line, ":")
+		name, rule = strings.TrimSpace(name), strings.TrimSpace(rule)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("line %d: missing holiday name", n)
+		}
+		h, err := parseHoliday(name, rule)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		hs = append(hs, h)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return hs, nil
+}
+
+// LoadHolidaysFile reads holidays from the named file as LoadHolidays.
+func LoadHolidaysFile(name string) (Holidays, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadHolidays(f)
+}
+
+// parseHoliday returns the named holiday falling on the date expression
+// rule.
+func parseHoliday(name, rule string) (HolidayProvider, error) {
+	e, err := ParseExpr(rule)
+	if err != nil {
+		return nil, err
+	}
+	if e.Clock != nil || e.Location != nil {
+		return nil, fmt.Errorf("unsupported holiday date %q", rule)
+	}
+	switch d := e.Date.(type) {
+	case *DayOfMonth:
+		if d.Month.Month != 0 && d.Day > 0 && len(e.Terms)+len(e.Offsets) == 0 {
+			return FixedHoliday{name, d.Month.Month, d.Day}, nil
+		}
+	case *NthWeekday:
+		if d.Month.Month != 0 && len(e.Terms)+len(e.Offsets) == 0 {
+			return WeekdayHoliday{name, d.Month.Month, d.Weekday, d.N}, nil
+		}
+	case *HolidayDate:
+		if holidayKey(d.Name) != "easter" || d.Year != 0 {
+			break
+		}
+		days, ok := termDays(e.Terms)
+		offset, offsetOK := termDays(e.Offsets)
+		if !ok || !offsetOK {
+			break
+		}
+		if e.Sub {
+			days = -days
+		}
+		return EasterHoliday{name, days + offset}, nil
+	}
+	return nil, fmt.Errorf("unsupported holiday date %q", rule)
+}
+
+// termDays returns the total of terms in days, or false if a term is not
+// in days or weeks.
+func termDays(terms []Term) (int, bool) {
+	days := 0
+	for _, t := range terms {
+		switch t.Unit {
+		case Day:
+			days += t.N
+		case Week:
+			days += 7 * t.N
+		default:
+			return 0, false
+		}
+	}
+	return days, true
+}
+
+var holidayKeyReplacer = strings.NewReplacer("'", "", "’", "", "-", " ")
+
+// holidayKey returns the name of a holiday in lower case without
+// apostrophes, with words separated by single spaces.
+func holidayKey(name string) string {
+	name = holidayKeyReplacer.Replace(name)
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// holidayLen returns the length of the holiday name at the start of s,
+// or zero if s does not start with it. The name must be a holiday key.
+func holidayLen(s, key string) int {
+	i := 0
+	for k := 0; k < len(key); {
+		if i == len(s) {
+			return 0
+		}
+		r, width := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == '\'' || r == '’':
+			i += width
+			continue
+		case key[k] == ' ':
+			if !unicode.IsSpace(r) && r != '-' {
+				return 0
+			}
+			for i < len(s) && (unicode.IsSpace(rune(s[i])) || s[i] == '-') {
+				i++
+			}
+			k++
+			continue
+		}
+		kr, kwidth := utf8.DecodeRuneInString(key[k:])
+		if unicode.ToLower(r) != kr {
+			return 0
+		}
+		i += width
+		k += kwidth
+	}
+	if r, _ := utf8.DecodeRuneInString(s[i:]); unicode.IsLetter(r) || unicode.IsDigit(r) {
+		return 0
+	}
+	return i
+}
+
+// isHoliday reports whether the date of t is a holiday of h.
+func isHoliday(h HolidayProvider, t time.Time) bool {
+	y, M, d := t.Date()
+	for _, name := range h.Names() {
+		if hM, hd, ok := h.Date(name, y); ok && hM == M && hd == d {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,231 @@
+package when
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseHoliday(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	tests := []testcase{
+		{
+			"Christmas",
+			time.Date(2006, time.December, 25, 0, 0, 0, 0, loc),
+		},
+		{
+			"thanksgiving 2025",
+			time.Date(2025, time.November, 27, 0, 0, 0, 0, loc),
+		},
+		{
+			"2 days before Easter",
+			time.Date(2006, time.April, 14, 0, 0, 0, 0, loc),
+		},
+		{
+			"the day after Labor Day",
+			time.Date(2006, time.September, 5, 0, 0, 0, 0, loc),
+		},
+		{
+			"the week before christmas 2025",
+			time.Date(2025, time.December, 18, 0, 0, 0, 0, loc),
+		},
+		{
+			"New Year's Day",
+			time.Date(2007, time.January, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			"new years eve at 11pm",
+			time.Date(2006, time.December, 31, 23, 0, 0, 0, loc),
+		},
+		{
+			"3pm on christmas eve",
+			time.Date(2006, time.December, 24, 15, 0, 0, 0, loc),
+		},
+		{
+			"memorial day 2026 + 1d",
+			time.Date(2026, time.May, 26, 0, 0, 0, 0, loc),
+		},
+		{
+			"christmas 2025 3pm",
+			time.Date(2025, time.December, 25, 15, 0, 0, 0, loc),
+		},
+		{
+			"the day after tomorrow",
+			time.Date(2006, time.January, 4, 0, 0, 0, 0, loc),
+		},
+	}
+	for _, tc := range tests {
+		tc.apply(t, now)
+	}
+}
+
+func TestParseHolidayOptions(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	p := New(WithBias(Past))
+	have, err := p.ParseNow("Christmas", now)
+	if err != nil {
+		t.Fatalf("ParseNow(%q) %v", "Christmas", err)
+	}
+	if want := time.Date(2005, time.December, 25, 0, 0, 0, 0, loc); !have.Equal(want) {
+		t.Errorf("ParseNow(%q)\nhave %v\nwant %v", "Christmas", have, want)
+	}
+	p = New(WithHolidays(Holidays{FixedHoliday{"Founders Day", time.March, 3}}))
+	have, err = p.ParseNow("founders day", now)
+	if err != nil {
+		t.Fatalf("ParseNow(%q) %v", "founders day", err)
+	}
+	if want := time.Date(2006, time.March, 3, 0, 0, 0, 0, loc); !have.Equal(want) {
+		t.Errorf("ParseNow(%q)\nhave %v\nwant %v", "founders day", have, want)
+	}
+	if _, err := p.ParseNow("christmas", now); err == nil {
+		t.Errorf("ParseNow(%q)\nwant parse error", "christmas")
+	}
+	e, err := ParseExpr("christmas")
+	if err != nil {
+		t.Fatalf("ParseExpr(%q) %v", "christmas", err)
+	}
+	if _, err := p.Eval(e, now); err == nil || !strings.Contains(err.Error(), "unknown holiday") {
+		t.Errorf("Eval(%q)\nhave %v\nwant unknown holiday", "christmas", err)
+	}
+}
+
+func TestEaster(t *testing.T) {
+	tests := []struct {
+		year  int
+		month time.Month
+		day   int
+	}{
+		{1961, time.April, 2},
+		{2006, time.April, 16},
+		{2008, time.March, 23},
+		{2019, time.April, 21},
+		{2024, time.March, 31},
+		{2025, time.April, 20},
+		{2038, time.April, 25},
+	}
+	for _, tt := range tests {
+		M, d := easter(tt.year)
+		if M != tt.month || d != tt.day {
+			t.Errorf("easter(%d)\nhave %v %d\nwant %v %d", tt.year, M, d, tt.month, tt.day)
+		}
+	}
+}
+
+func TestHolidayProviders(t *testing.T) {
+	tests := []struct {
+		h     HolidayProvider
+		name  string
+		year  int
+		month time.Month
+		day   int
+		ok    bool
+	}{
+		{FixedHoliday{"Christmas", time.December, 25}, "CHRISTMAS", 2006, time.December, 25, true},
+		{FixedHoliday{"New Year's Day", time.January, 1}, "new  years day", 2006, time.January, 1, true},
+		{FixedHoliday{"Christmas", time.December, 25}, "Boxing Day", 2006, 0, 0, false},
+		{WeekdayHoliday{"Thanksgiving", time.November, time.Thursday, 4}, "Thanksgiving", 2006, time.November, 23, true},
+		{WeekdayHoliday{"Memorial Day", time.May, time.Monday, -1}, "Memorial Day", 2006, time.May, 29, true},
+		{EasterHoliday{"Good Friday", -2}, "good friday", 2008, time.March, 21, true},
+		{EasterHoliday{"Ascension Day", 39}, "Ascension Day", 2006, time.May, 25, true},
+		{DefaultHolidays, "Labor Day", 2006, time.September, 4, true},
+		{DefaultHolidays, "Arbor Day", 2006, 0, 0, false},
+	}
+	for _, tt := range tests {
+		M, d, ok := tt.h.Date(tt.name, tt.year)
+		if M != tt.month || d != tt.day || ok != tt.ok {
+			t.Errorf("Date(%q, %d)\nhave %v %d %v\nwant %v %d %v", tt.name, tt.year, M, d, ok, tt.month, tt.day, tt.ok)
+		}
+	}
+}
+
+func TestLoadHolidays(t *testing.T) {
+	in := `# Company holidays
+Founders Day: March 3rd
+
+Harvest Festival: the 2nd Monday of October
+Arbor Day: the last Friday of April
+Ascension Day: 39 days after Easter
+Pentecost: Easter + 7 weeks
+Carnival: 2 days before easter
+`
+	have, err := LoadHolidays(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("LoadHolidays %v", err)
+	}
+	want := Holidays{
+		FixedHoliday{"Founders Day", time.March, 3},
+		WeekdayHoliday{"Harvest Festival", time.October, time.Monday, 2},
+		WeekdayHoliday{"Arbor Day", time.April, time.Friday, -1},
+		EasterHoliday{"Ascension Day", 39},
+		EasterHoliday{"Pentecost", 49},
+		EasterHoliday{"Carnival", -2},
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("LoadHolidays\nhave %v\nwant %v", have, want)
+	}
+	name := filepath.Join(t.TempDir(), "holidays.txt")
+	if err := os.WriteFile(name, []byte(in), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	have, err = LoadHolidaysFile(name)
+	if err != nil {
+		t.Fatalf("LoadHolidaysFile %v", err)
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("LoadHolidaysFile\nhave %v\nwant %v", have, want)
+	}
+}
+
+func TestLoadHolidaysError(t *testing.T) {
+	tests := []string{
+		"Founders Day",
+		": March 3rd",
+		"Founders Day: 3pm",
+		"Founders Day: March 3rd at 3pm",
+		"Founders Day: tomorrow",
+		"Founders Day: the 3rd",
+		"Boxing Day: the day after Christmas",
+		"Ascension Day: 39 hours after Easter",
+		"Ascension Day: Easter 2006 + 39 days",
+		"Founders Day: invalid",
+	}
+	for _, tt := range tests {
+		have, err := LoadHolidays(strings.NewReader(tt))
+		if err == nil {
+			t.Errorf("LoadHolidays(%q)\nhave %v\nwant error", tt, have)
+		}
+	}
+}
+
+func TestWorkWeekObserved(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := &WorkWeek{
+		Days:     defaultCalendar.Days,
+		Start:    9 * time.Hour,
+		End:      17 * time.Hour,
+		Observed: DefaultHolidays,
+	}
+	p := New(WithCalendar(c))
+	now := time.Date(2006, time.December, 22, 15, 4, 5, 0, loc)
+	have, err := p.ParseNow("2 business days", now)
+	if err != nil {
+		t.Fatalf("ParseNow %v", err)
+	}
+	if want := time.Date(2006, time.December, 28, 15, 4, 5, 0, loc); !have.Equal(want) {
+		t.Errorf("ParseNow(%q)\nhave %v\nwant %v", "2 business days", have, want)
+	}
+}
@@ -0,0 +1,81 @@
+package when
+
+import (
+	"math"
+	"time"
+)
+
+// Humanize returns t described relative to now.
+func Humanize(t, now time.Time) string {
+	return defaultParser.Humanize(t, now)
+}
+
+// Humanize returns t described relative to now in the grammar accepted
+// by the parser, such as "3 hours ago", "tomorrow at 3pm" or
+// "March 14th". Times within a few hours of now are described by their
+// distance from now, rounded to the nearest minute or hour. Other times
+// are described by the first of their day, weekday, month and day, or
+// calendar date that the parser resolves back to t, at the minute.
+func (p *Parser) Humanize(t, now time.Time) string {
+	if p.loc != nil {
+		now = now.In(p.loc)
+	}
+	t = t.In(now.Location())
+	d := t.Sub(now)
+	if d < 0 {
+		d = -d
+	}
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < 6*time.Hour:
+		return p.humanizeDuration(t, now, d)
+	}
+	y, M, day := t.Date()
+	var c *Clock
+	if h, m, _ := t.Clock(); h != 0 || m != 0 {
+		c = &Clock{Hour: h, Minute: m, Precision: Hour}
+		if m != 0 {
+			c.Precision = Minute
+		}
+	}
+	want := Minute.truncate(t, p.weekStart, time.January)
+	for _, date := range humanizeDates(t) {
+		e := &Expr{Date: date, Clock: c}
+		if r, err := p.Eval(e, now); err == nil && r.Equal(want) {
+			return Format(e)
+		}
+	}
+	e := &Expr{Date: &CalendarDate{Year: y, Month: M, Day: day}, Clock: c}
+	return Format(e)
+}
+
+// humanizeDuration describes t as a distance d from now.
+func (p *Parser) humanizeDuration(t, now time.Time, d time.Duration) string {
+	term := Term{int(math.Round(d.Minutes())), Minute}
+	if term.N >= 60 {
+		term = Term{int(math.Round(d.Hours())), Hour}
+	}
+	e := &Expr{Terms: []Term{term}}
+	if t.Before(now) {
+		e.Sub = true
+		return Format(e)
+	}
+	return Format(e) + " from now"
+}
+
+// humanizeDates returns the dates that might describe the day of t, from
+// most to least familiar.
+func humanizeDates(t time.Time) []Date {
+	_, M, d := t.Date()
+	w := t.Weekday()
+	return []Date{
+		&RelativeDay{Days: 0},
+		&RelativeDay{Days: 1},
+		&RelativeDay{Days: -1},
+		&WeekdayDate{Weekday: w},
+		&WeekdayDate{Weekday: w, Qualifier: Last},
+		&WeekdayDate{Weekday: w, Qualifier: Next},
+		&DayOfMonth{Day: d, Month: MonthRef{Month: M}},
+	}
+}
@@ -0,0 +1,66 @@
+package when
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHumanize(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	tests := []struct {
+		in   time.Time
+		want string
+	}{
+		{now.Add(20 * time.Second), "now"},
+		{now.Add(-5 * time.Minute), "5 minutes ago"},
+		{now.Add(90 * time.Second), "2 minutes from now"},
+		{now.Add(-3*time.Hour - 10*time.Minute), "3 hours ago"},
+		{now.Add(59*time.Minute + 50*time.Second), "1 hour from now"},
+		{time.Date(2006, time.January, 2, 23, 30, 0, 0, loc), "today at 11:30pm"},
+		{time.Date(2006, time.January, 3, 15, 0, 0, 0, loc), "tomorrow at 3pm"},
+		{time.Date(2006, time.January, 1, 0, 0, 0, 0, loc), "yesterday"},
+		{time.Date(2006, time.January, 6, 12, 0, 0, 0, loc), "Friday at noon"},
+		{time.Date(2005, time.December, 29, 9, 0, 0, 0, loc), "last Thursday at 9am"},
+		{time.Date(2006, time.January, 10, 8, 0, 0, 0, loc), "next Tuesday at 8am"},
+		{time.Date(2006, time.March, 14, 0, 0, 0, 0, loc), "March 14th"},
+		{time.Date(2005, time.March, 14, 18, 45, 0, 0, loc), "2005-03-14 at 6:45pm"},
+		{time.Date(2008, time.March, 14, 0, 0, 0, 0, loc), "2008-03-14"},
+	}
+	for _, tt := range tests {
+		have := Humanize(tt.in, now)
+		if have != tt.want {
+			t.Errorf("Humanize(%v)\nhave %q\nwant %q", tt.in, have, tt.want)
+		}
+	}
+}
+
+func TestHumanizeParse(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	parsers := []*Parser{New(), New(WithBias(Past)), New(WithNext(NextOccurrence), WithWeekStart(time.Monday))}
+	for _, p := range parsers {
+		for d := -800 * time.Hour; d <= 800*time.Hour; d += 97 * time.Minute {
+			in := now.Add(d)
+			s := p.Humanize(in, now)
+			have, err := p.ParseNow(s, now)
+			if err != nil {
+				t.Errorf("ParseNow(%q)\nunexpected error: %v", s, err)
+				continue
+			}
+			diff := have.Sub(in)
+			if diff < 0 {
+				diff = -diff
+			}
+			if diff > 30*time.Minute {
+				t.Errorf("ParseNow(%q)\nhave %v\nwant %v", s, have, in)
+			}
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package when
+
+import (
+	"sort"
+	"time"
+)
+
+// Interpretation is one reading of an expression that may be understood
+// in more than one way.
+type Interpretation struct {
+	Time time.Time
+	// Confidence is the likelihood of the interpretation, from 0 to 1.
+	// The confidences of all interpretations of an expression sum to 1.
+	Confidence float64
+	// Reason describes the reading, as in "the next occurrence" or
+	// "day first".
+	Reason string
+}
+
+// candidate is a reading of an expression evaluated by the policies of
+// a parser. Reason describes the reading and primary describes the
+// reading of the parser it is an alternative to.
+type candidate struct {
+	p       *Parser
+	e       *Expr
+	weight  float64
+	reason  string
+	primary string
+}
+
+var biasReasons = [...]string{
+	Future: "the next occurrence",
+	Past:   "the most recent occurrence",
+}
+
+var nextReasons = [...]string{
+	NextPeriod:     "in the following week or year",
+	NextOccurrence: "the first occurrence after today",
+}
+
+var dateOrderReasons = [...]string{
+	MDY: "month first",
+	DMY: "day first",
+	YMD: "year first",
+}
+
+// ParseAll returns the plausible interpretations of s, most likely
+// first.
+func ParseAll(s string) ([]Interpretation, error) {
+	return defaultParser.ParseAll(s)
+}
+
+// ParseAllNow returns the plausible interpretations of s relative to
+// now, most likely first.
+func ParseAllNow(s string, now time.Time) ([]Interpretation, error) {
+	return defaultParser.ParseAllNow(s, now)
+}
+
+// ParseAll returns the plausible interpretations of s relative to the
+// reference time, most likely first.
+func (p *Parser) ParseAll(s string) ([]Interpretation, error) {
+	return p.ParseAllNow(s, p.now())
+}
+
+// ParseAllNow returns the plausible interpretations of s relative to
+// now, most likely first. The time derived by ParseNow is always among
+// them. The alternatives are the other direction of the bias, the
+// current week of a named weekday, the current month of a day of the
+// month, the other NextPolicy, the other orders of a numeric date, and a
+// time of day or day of the month for a bare number of one or two
+// digits. Readings of the same time are merged. An ambiguous numeric
+// date is not rejected by WithStrictDateOrder but read in every order.
+func (p *Parser) ParseAllNow(s string, now time.Time) ([]Interpretation, error) {
+	q := *p
+	q.strictDates = false
+	e, err := q.ParseExpr(s)
+	if err != nil {
+		return nil, err
+	}
+	t, err := q.Eval(e, now)
+	if err != nil {
+		return nil, err
+	}
+	weight := 1.0
+	if isBareNumber(e) {
+		weight = 0.2
+	}
+	all := []Interpretation{{Time: t, Reason: "the only interpretation"}}
+	weights := []float64{weight}
+	for _, c := range q.candidates(s, e) {
+		u, err := c.p.Eval(c.e, now)
+		if err != nil {
+			continue
+		}
+		if u.Equal(t) {
+			continue
+		}
+		if len(all) == 1 {
+			all[0].Reason = c.primary
+		}
+		i := 1
+		for ; i < len(all) && !all[i].Time.Equal(u); i++ {
+		}
+		if i == len(all) {
+			all = append(all, Interpretation{Time: u, Reason: c.reason})
+			weights = append(weights, 0)
+		}
+		weights[i] += c.weight
+	}
+	var sum float64
+	for _, w := range weights {
+		sum += w
+	}
+	for i := range all {
+		all[i].Confidence = weights[i] / sum
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Confidence > all[j].Confidence
+	})
+	return all, nil
+}
+
+// candidates returns the alternative readings of e, the expression
+// parsed from s by p. Readings particular to the date come before the
+// other policies, so they name the interpretations they share.
+func (p *Parser) candidates(s string, e *Expr) []candidate {
+	var c []candidate
+	for _, o := range []DateOrder{MDY, DMY, YMD} {
+		if o == p.dateOrder {
+			continue
+		}
+		q := *p
+		q.dateOrder = o
+		alt, err := q.ParseExpr(s)
+		if err != nil {
+			continue
+		}
+		weight := 0.5
+		if o == YMD || p.dateOrder == YMD {
+			weight = 0.2
+		}
+		c = append(c, candidate{&q, alt, weight, dateOrderReasons[o], dateOrderReasons[p.dateOrder]})
+	}
+	primary := biasReasons[p.bias]
+	switch d := e.Date.(type) {
+	case *WeekdayDate:
+		if d.Qualifier == Nearest {
+			alt := *e
+			alt.Date = &WeekdayDate{d.Span, d.Weekday, This}
+			c = append(c, candidate{p, &alt, 0.5, "in the current week", primary})
+		}
+	case *DayOfMonth:
+		if d.Month == (MonthRef{}) {
+			alt := *e
+			alt.Date = &DayOfMonth{d.Span, d.Day, MonthRef{Relative: true}}
+			c = append(c, candidate{p, &alt, 0.5, "in the current month", primary})
+		}
+	case *NthWeekday:
+		if d.Month == (MonthRef{}) {
+			alt := *e
+			alt.Date = &NthWeekday{d.Span, d.N, d.Weekday, MonthRef{Relative: true}}
+			c = append(c, candidate{p, &alt, 0.5, "in the current month", primary})
+		}
+	case *CalendarDate:
+		if isBareNumber(e) {
+			c = append(c, bareNumberCandidates(p, e, d.Year)...)
+		}
+	}
+	next := *p
+	next.next = 1 - p.next
+	c = append(c, candidate{&next, e, 0.5, nextReasons[next.next], nextReasons[p.next]})
+	bias := *p
+	bias.bias = 1 - p.bias
+	c = append(c, candidate{&bias, e, 0.4, biasReasons[bias.bias], biasReasons[p.bias]})
+	return c
+}
+
+// isBareNumber reports whether the anchor of e is a number of one or two
+// digits read as a year, as in "5".
+func isBareNumber(e *Expr) bool {
+	d, ok := e.Date.(*CalendarDate)
+	return ok && d.Month == 0 && d.End-d.Pos <= 2 && e.Clock == nil
+}
+
+// bareNumberCandidates returns the readings of the bare number n as a
+// day of the month and as an hour of the day. An hour before noon is
+// also read in the afternoon, as in "5" for 5am and 5pm.
+func bareNumberCandidates(p *Parser, e *Expr, n int) []candidate {
+	var c []candidate
+	const primary = "as a year"
+	if n >= 1 && n <= 31 {
+		alt := *e
+		alt.Date = &DayOfMonth{Span: e.Date.span(), Day: n}
+		c = append(c, candidate{p, &alt, 0.4, "as a day of the month", primary})
+	}
+	if n <= 23 {
+		alt := *e
+		alt.Date = nil
+		alt.Clock = &Clock{Hour: n, Precision: Hour}
+		reason := "as a time of day"
+		if n < 12 {
+			reason = "as a morning time"
+		}
+		c = append(c, candidate{p, &alt, 0.3, reason, primary})
+	}
+	if n >= 1 && n <= 11 {
+		alt := *e
+		alt.Date = nil
+		alt.Clock = &Clock{Hour: n + 12, Precision: Hour}
+		c = append(c, candidate{p, &alt, 0.4, "as an afternoon time", primary})
+	}
+	return c
+}
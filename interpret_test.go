@@ -0,0 +1,166 @@
+package when
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseAll(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	friday := time.Date(2006, time.January, 6, 15, 4, 5, 0, loc)
+	tests := []struct {
+		in   string
+		now  time.Time
+		want []Interpretation
+	}{
+		{
+			"tomorrow",
+			now,
+			[]Interpretation{
+				{time.Date(2006, time.January, 3, 0, 0, 0, 0, loc), 1, "the only interpretation"},
+			},
+		},
+		{
+			"Friday",
+			now,
+			[]Interpretation{
+				{time.Date(2006, time.January, 6, 0, 0, 0, 0, loc), 0.71, "the next occurrence"},
+				{time.Date(2005, time.December, 30, 0, 0, 0, 0, loc), 0.29, "the most recent occurrence"},
+			},
+		},
+		{
+			"Friday",
+			friday,
+			[]Interpretation{
+				{time.Date(2006, time.January, 13, 0, 0, 0, 0, loc), 0.53, "the next occurrence"},
+				{time.Date(2006, time.January, 6, 0, 0, 0, 0, loc), 0.26, "in the current week"},
+				{time.Date(2005, time.December, 30, 0, 0, 0, 0, loc), 0.21, "the most recent occurrence"},
+			},
+		},
+		{
+			"next Friday",
+			now,
+			[]Interpretation{
+				{time.Date(2006, time.January, 13, 0, 0, 0, 0, loc), 0.67, "in the following week or year"},
+				{time.Date(2006, time.January, 6, 0, 0, 0, 0, loc), 0.33, "the first occurrence after today"},
+			},
+		},
+		{
+			"the 2nd",
+			now,
+			[]Interpretation{
+				{time.Date(2006, time.February, 2, 0, 0, 0, 0, loc), 0.53, "the next occurrence"},
+				{time.Date(2006, time.January, 2, 0, 0, 0, 0, loc), 0.47, "in the current month"},
+			},
+		},
+		{
+			"the 3rd at 9am",
+			friday,
+			[]Interpretation{
+				{time.Date(2006, time.February, 3, 9, 0, 0, 0, loc), 0.53, "the next occurrence"},
+				{time.Date(2006, time.January, 3, 9, 0, 0, 0, loc), 0.47, "in the current month"},
+			},
+		},
+		{
+			"5",
+			now,
+			[]Interpretation{
+				{time.Date(2006, time.January, 5, 0, 0, 0, 0, loc), 0.31, "as a day of the month"},
+				{time.Date(2006, time.January, 2, 17, 0, 0, 0, loc), 0.31, "as an afternoon time"},
+				{time.Date(2006, time.January, 2, 5, 0, 0, 0, loc), 0.23, "as a morning time"},
+				{time.Date(5, time.January, 1, 0, 0, 0, 0, loc), 0.15, "as a year"},
+			},
+		},
+		{
+			"20",
+			now,
+			[]Interpretation{
+				{time.Date(2006, time.January, 20, 0, 0, 0, 0, loc), 0.44, "as a day of the month"},
+				{time.Date(2006, time.January, 2, 20, 0, 0, 0, loc), 0.33, "as a time of day"},
+				{time.Date(20, time.January, 1, 0, 0, 0, 0, loc), 0.22, "as a year"},
+			},
+		},
+		{
+			"01/02/2006",
+			now,
+			[]Interpretation{
+				{time.Date(2006, time.January, 2, 0, 0, 0, 0, loc), 0.67, "month first"},
+				{time.Date(2006, time.February, 1, 0, 0, 0, 0, loc), 0.33, "day first"},
+			},
+		},
+		{
+			"13/02/2006",
+			now,
+			[]Interpretation{
+				{time.Date(2006, time.February, 13, 0, 0, 0, 0, loc), 1, "the only interpretation"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		have, err := ParseAllNow(tt.in, tt.now)
+		if err != nil {
+			t.Errorf("%q unexpected error: %v", tt.in, err)
+			continue
+		}
+		if len(have) != len(tt.want) {
+			t.Errorf("%q have %d interpretations, want %d\n%v", tt.in, len(have), len(tt.want), have)
+			continue
+		}
+		for i, want := range tt.want {
+			h := have[i]
+			if !h.Time.Equal(want.Time) || h.Reason != want.Reason || math.Abs(h.Confidence-want.Confidence) > 0.005 {
+				t.Errorf("%q interpretation %d\nhave %v %.2f %q\nwant %v %.2f %q", tt.in, i, h.Time, h.Confidence, h.Reason, want.Time, want.Confidence, want.Reason)
+			}
+		}
+	}
+}
+
+func TestParseAllOptions(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	p := New(WithBias(Past), WithStrictDateOrder(), WithDateOrder(DMY))
+	tests := []struct {
+		in   string
+		want []time.Time
+	}{
+		{"Friday", []time.Time{
+			time.Date(2005, time.December, 30, 0, 0, 0, 0, loc),
+			time.Date(2006, time.January, 6, 0, 0, 0, 0, loc),
+		}},
+		{"01/02/2006", []time.Time{
+			time.Date(2006, time.February, 1, 0, 0, 0, 0, loc),
+			time.Date(2006, time.January, 2, 0, 0, 0, 0, loc),
+		}},
+	}
+	for _, tt := range tests {
+		have, err := p.ParseAllNow(tt.in, now)
+		if err != nil {
+			t.Errorf("%q unexpected error: %v", tt.in, err)
+			continue
+		}
+		if len(have) != len(tt.want) {
+			t.Errorf("%q have %d interpretations, want %d", tt.in, len(have), len(tt.want))
+			continue
+		}
+		for i, want := range tt.want {
+			if !have[i].Time.Equal(want) {
+				t.Errorf("%q interpretation %d\nhave %v\nwant %v", tt.in, i, have[i].Time, want)
+			}
+		}
+	}
+}
+
+func TestParseAllError(t *testing.T) {
+	_, err := ParseAll("1 year2 months")
+	if _, ok := err.(*ParseError); !ok {
+		t.Fatalf("have %v, want *ParseError", err)
+	}
+}
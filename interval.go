@@ -0,0 +1,285 @@
+package when
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Interval is the span of time between two parsed endpoints.
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns the length of the interval.
+func (i Interval) Duration() time.Duration {
+	return i.End.Sub(i.Start)
+}
+
+// ParseInterval returns the derived interval.
+func ParseInterval(s string) (Interval, error) {
+	return defaultParser.ParseInterval(s)
+}
+
+// ParseIntervalNow returns the derived interval relative to now.
+func ParseIntervalNow(s string, now time.Time) (Interval, error) {
+	return defaultParser.ParseIntervalNow(s, now)
+}
+
+// ParseInterval returns the derived interval relative to the reference time.
+func (p *Parser) ParseInterval(s string) (Interval, error) {
+	return p.ParseIntervalNow(s, p.now())
+}
+
+// ParseIntervalNow returns the derived interval relative to now.
+//
+// Intervals are written as "from X to Y", "between X and Y", "X until Y",
+// "X through Y" or "X-Y", where the leading "from" is optional. Without
+// a start, as in "until Friday", the interval starts at now. An endpoint
+// with only a time of day takes its date from the other endpoint, so
+// "from 3pm to 5pm tomorrow" is entirely tomorrow. Where the end would
+// come before the start, the end is resolved from the start, or with a
+// bias to the past the start from the end, so "Monday to Friday" on a
+// Wednesday is next week, or last week when biased to the past. The
+// interval ends at Y, except with "through" where it ends after the
+// period of Y, so "Monday through Friday" includes all of Friday. A date
+// before "from" is the date of both endpoints, as in "Friday from 3pm to
+// 5pm". A bare number is an hour where the other endpoint is a time of
+// day or also a bare number, so "tomorrow from 9 to 5" is 9am to 5pm
+// tomorrow and "between 2 and 4pm" is 2pm to 4pm. An interval that
+// would end before it starts is an error. Without any separator the
+// interval is the range of the expression.
+func (p *Parser) ParseIntervalNow(s string, now time.Time) (Interval, error) {
+	i, err := p.parseInterval(s, now)
+	if err != nil {
+		if e, ok := err.(*ParseError); ok {
+			e.setInput(s)
+		}
+		return Interval{}, err
+	}
+	return i, nil
+}
+
+func (p *Parser) parseInterval(s string, now time.Time) (Interval, error) {
+	tokens, err := p.lex(s)
+	if err != nil {
+		return Interval{}, err
+	}
+	between := len(tokens) > 0 && tokens[0].typ == tokenKeyword && tokens[0].val == "between"
+	if len(tokens) > 0 && (between || tokens[0].typ == tokenFrom) {
+		tokens = tokens[1:]
+	}
+	var date *Expr
+	if k := sharedDate(tokens); k > 0 && !between {
+		date, err = parseTokens(tokens[:k], tokens[k].pos, p)
+		if err != nil {
+			return Interval{}, err
+		}
+		if date.Date == nil || date.Clock != nil || len(date.Terms) > 0 || len(date.Offsets) > 0 {
+			return Interval{}, newParseError(span(tokens[0], tokens[k-1]), "expected date")
+		}
+		tokens = tokens[k+1:]
+	}
+	i := intervalSeparator(tokens, between)
+	if i < 0 {
+		if between {
+			return Interval{}, newParseError(token{pos: len(s), end: len(s)}, "missing interval separator", tokenKeyword)
+		}
+		r, err := p.ParseRangeNow(s, now)
+		return Interval{r.Start, r.End}, err
+	}
+	sep := tokens[i]
+	if i == 0 && (date != nil || sep.val != "until" && sep.val != "through") {
+		return Interval{}, newParseError(sep, "missing interval start")
+	}
+	if i == len(tokens)-1 {
+		return Interval{}, newParseError(token{pos: len(s), end: len(s)}, "missing interval end")
+	}
+	end, err := parseTokens(tokens[i+1:], len(s), p)
+	if err != nil {
+		return Interval{}, err
+	}
+	start := &Expr{}
+	if i > 0 {
+		start, err = parseTokens(tokens[:i], sep.pos, p)
+		if err != nil {
+			return Interval{}, err
+		}
+	}
+	bare := bareHours(start, end)
+	if date != nil {
+		if err := shareDate(date, start, end); err != nil {
+			return Interval{}, newParseError(span(tokens[0], tokens[i-1]), err.Error())
+		}
+	}
+	start.Input, end.Input = s, s
+	a, _, err := p.anchor(start, now)
+	if err != nil {
+		return Interval{}, err
+	}
+	b, g, err := p.anchor(end, now)
+	if err != nil {
+		return Interval{}, err
+	}
+	if start.Date != nil && end.Date != nil && b.Before(a) {
+		// The endpoint resolved against the bias is resolved again from
+		// the other, so "Monday to Friday" never ends before it starts.
+		switch {
+		case p.bias == Past && biased(start.Date):
+			a, _, err = p.anchor(start, b)
+		case p.bias != Past && biased(end.Date):
+			b, g, err = p.anchor(end, a)
+		}
+		if err != nil {
+			return Interval{}, err
+		}
+	}
+	switch {
+	case start.timeOnly() && end.Date != nil:
+		a = withDate(a, b)
+	case end.timeOnly() && start.Date != nil:
+		b = withDate(b, a)
+	}
+	if sep.val == "through" {
+		b = g.add(p.truncate(end.Date, g, b), 1)
+	}
+	var r Interval
+	r.Start, err = p.apply(start, a)
+	if err != nil {
+		return Interval{}, err
+	}
+	r.End, err = p.apply(end, b)
+	if err != nil {
+		return Interval{}, err
+	}
+	switch {
+	case r.End.Before(r.Start) && end.timeOnly() && !bare:
+		// A time of day before the start is on the following day, as
+		// in "10pm to 2am".
+		r.End = r.End.AddDate(0, 0, 1)
+	case r.End.Before(r.Start) && start.timeOnly() && end.Date != nil:
+		// A time of day after a dated end is on the day before, as in
+		// "10pm to 2am tomorrow".
+		r.Start = r.Start.AddDate(0, 0, -1)
+	}
+	if r.End.Before(r.Start) {
+		return Interval{}, newParseError(token{pos: 0, end: len(s)}, "interval ends before it starts")
+	}
+	r.Start, r.End = p.in(r.Start, now), p.in(r.End, now)
+	return r, nil
+}
+
+// intervalSeparator returns the index of the token separating the
+// endpoints of an interval, or -1 if there is none.
+func intervalSeparator(tokens []token, between bool) int {
+	for i, t := range tokens {
+		switch {
+		case between:
+			if t.typ == tokenKeyword && t.val == "and" {
+				return i
+			}
+		case t.typ == tokenKeyword:
+			switch t.val {
+			case "until", "through":
+				return i
+			case "to":
+				if i == 0 || tokens[i-1].val != "quarter" {
+					return i
+				}
+			}
+		case t.typ == tokenOperatorSub:
+			// A subtraction followed by a duration is arithmetic.
+			if i+2 >= len(tokens) || tokens[i+1].typ != tokenDigit || tokens[i+2].typ != tokenUnit {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// sharedDate returns the index of the "from" following a date shared by
+// the endpoints of an interval, as in "Friday from 3pm to 5pm", or 0 if
+// there is none. The "from" of a duration, as in "2 hours from now", is
+// not.
+func sharedDate(tokens []token) int {
+	for i, t := range tokens {
+		if t.typ == tokenFrom && strings.EqualFold(t.val, "from") {
+			if i > 0 && tokens[i-1].typ != tokenUnit {
+				return i
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// shareDate applies the date of the expression date to the start of an
+// interval, which must be a time of day or a bare number read as an
+// hour, and so to an end without a date.
+func shareDate(date, start, end *Expr) error {
+	if h, ok := bareHour(start); ok {
+		start.Date = nil
+		start.Clock = &Clock{Hour: h, Precision: Hour}
+	}
+	if !start.timeOnly() {
+		return errors.New("expected time of day")
+	}
+	start.Date = date.Date
+	return nil
+}
+
+// bareHours reads a bare number at an endpoint of an interval as an hour
+// where the other endpoint is a time of day or also a bare number, as in
+// "from 9 to 5" and "between 2 and 4pm", and reports whether the end is
+// such an hour. A bare start hour is read in the afternoon where the end
+// is later in the afternoon, and a bare end hour before the start hour
+// in the afternoon, so "9 to 5" is 9am to 5pm and "2 to 4pm" is 2pm to
+// 4pm.
+func bareHours(start, end *Expr) bool {
+	h, startBare := bareHour(start)
+	k, endBare := bareHour(end)
+	switch {
+	case startBare && (endBare || end.timeOnly()):
+	case endBare && start.timeOnly():
+	default:
+		return false
+	}
+	if startBare {
+		start.Date = nil
+		start.Clock = &Clock{Hour: h, Precision: Hour}
+	}
+	if endBare {
+		end.Date = nil
+		end.Clock = &Clock{Hour: k, Precision: Hour}
+	}
+	switch {
+	case startBare && !endBare && h < 12 && h+12 < end.Clock.Hour:
+		start.Clock.Hour += 12
+	case endBare && k < start.Clock.Hour && k < 12:
+		end.Clock.Hour += 12
+	}
+	return endBare
+}
+
+// bareHour returns the hour of the day named by e, a bare number read as
+// a year, as in "5", reporting whether it is one.
+func bareHour(e *Expr) (int, bool) {
+	if !isBareNumber(e) {
+		return 0, false
+	}
+	d := e.Date.(*CalendarDate)
+	return d.Year, d.Year <= 23 && len(e.Terms) == 0 && len(e.Offsets) == 0
+}
+
+// timeOnly reports whether the anchor is a time of day without a date.
+func (e *Expr) timeOnly() bool {
+	return e.Date == nil && e.Clock != nil
+}
+
+// withDate returns t moved to the date of d.
+func withDate(t, d time.Time) time.Time {
+	y, M, day := d.Date()
+	h, m, s := t.Clock()
+	return time.Date(y, M, day, h, m, s, t.Nanosecond(), t.Location())
+}
@@ -0,0 +1,247 @@
+package when
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseInterval(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	tests := []struct {
+		in   string
+		want Interval
+	}{
+		{
+			"from 3pm to 5pm tomorrow",
+			Interval{
+				time.Date(2006, time.January, 3, 15, 0, 0, 0, loc),
+				time.Date(2006, time.January, 3, 17, 0, 0, 0, loc),
+			},
+		},
+		{
+			"from tomorrow at 3pm to 5pm",
+			Interval{
+				time.Date(2006, time.January, 3, 15, 0, 0, 0, loc),
+				time.Date(2006, time.January, 3, 17, 0, 0, 0, loc),
+			},
+		},
+		{
+			"between Jan 12th and Jan 19th",
+			Interval{
+				time.Date(2006, time.January, 12, 0, 0, 0, 0, loc),
+				time.Date(2006, time.January, 19, 0, 0, 0, 0, loc),
+			},
+		},
+		{
+			"between 9:00 and 17:30 tomorrow",
+			Interval{
+				time.Date(2006, time.January, 3, 9, 0, 0, 0, loc),
+				time.Date(2006, time.January, 3, 17, 30, 0, 0, loc),
+			},
+		},
+		{
+			"9am-5pm",
+			Interval{
+				time.Date(2006, time.January, 2, 9, 0, 0, 0, loc),
+				time.Date(2006, time.January, 2, 17, 0, 0, 0, loc),
+			},
+		},
+		{
+			"10pm - 2am",
+			Interval{
+				time.Date(2006, time.January, 2, 22, 0, 0, 0, loc),
+				time.Date(2006, time.January, 3, 2, 0, 0, 0, loc),
+			},
+		},
+		{
+			"quarter to 4pm to 5pm",
+			Interval{
+				time.Date(2006, time.January, 2, 15, 45, 0, 0, loc),
+				time.Date(2006, time.January, 2, 17, 0, 0, 0, loc),
+			},
+		},
+		{
+			"Tuesday through Friday",
+			Interval{
+				time.Date(2006, time.January, 3, 0, 0, 0, 0, loc),
+				time.Date(2006, time.January, 7, 0, 0, 0, 0, loc),
+			},
+		},
+		{
+			"until Friday",
+			Interval{
+				now,
+				time.Date(2006, time.January, 6, 0, 0, 0, 0, loc),
+			},
+		},
+		{
+			"from now until 5pm",
+			Interval{
+				now,
+				time.Date(2006, time.January, 2, 17, 0, 0, 0, loc),
+			},
+		},
+		{
+			"now - 2 days to now",
+			Interval{
+				time.Date(2005, time.December, 31, 15, 4, 5, 0, loc),
+				now,
+			},
+		},
+		{
+			"Friday from 3pm to 5pm",
+			Interval{
+				time.Date(2006, time.January, 6, 15, 0, 0, 0, loc),
+				time.Date(2006, time.January, 6, 17, 0, 0, 0, loc),
+			},
+		},
+		{
+			"tomorrow from 9 to 5",
+			Interval{
+				time.Date(2006, time.January, 3, 9, 0, 0, 0, loc),
+				time.Date(2006, time.January, 3, 17, 0, 0, 0, loc),
+			},
+		},
+		{
+			"2006-01-06 from 10pm to 2am",
+			Interval{
+				time.Date(2006, time.January, 6, 22, 0, 0, 0, loc),
+				time.Date(2006, time.January, 7, 2, 0, 0, 0, loc),
+			},
+		},
+		{
+			"from 9 to 5",
+			Interval{
+				time.Date(2006, time.January, 2, 9, 0, 0, 0, loc),
+				time.Date(2006, time.January, 2, 17, 0, 0, 0, loc),
+			},
+		},
+		{
+			"between 2 and 4pm",
+			Interval{
+				time.Date(2006, time.January, 2, 14, 0, 0, 0, loc),
+				time.Date(2006, time.January, 2, 16, 0, 0, 0, loc),
+			},
+		},
+		{
+			"from 10pm to 2am tomorrow",
+			Interval{
+				time.Date(2006, time.January, 2, 22, 0, 0, 0, loc),
+				time.Date(2006, time.January, 3, 2, 0, 0, 0, loc),
+			},
+		},
+		{
+			"2 hours from now to tomorrow",
+			Interval{
+				time.Date(2006, time.January, 2, 17, 4, 5, 0, loc),
+				time.Date(2006, time.January, 3, 0, 0, 0, 0, loc),
+			},
+		},
+		{
+			"tomorrow",
+			Interval{
+				time.Date(2006, time.January, 3, 0, 0, 0, 0, loc),
+				time.Date(2006, time.January, 4, 0, 0, 0, 0, loc),
+			},
+		},
+	}
+	for _, tt := range tests {
+		have, err := ParseIntervalNow(tt.in, now)
+		if err != nil {
+			t.Fatalf("ParseInterval(%q) %v", tt.in, err)
+		}
+		if !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("ParseInterval(%q)\nhave %v\nwant %v", tt.in, have, tt.want)
+		}
+	}
+}
+
+func TestParseIntervalError(t *testing.T) {
+	var tests = []string{
+		"between 3pm",
+		"between 3pm to 5pm",
+		"to 5pm",
+		"from 3pm to",
+		"from 3pm to 5 apples",
+		"Friday from Monday to Tuesday",
+		"Friday from until 5pm",
+		"3pm from 4pm to 5pm",
+		"from 20 to 5",
+		"from 3pm tomorrow to 2pm tomorrow",
+	}
+	now := time.Now()
+	for _, tc := range tests {
+		have, err := ParseIntervalNow(tc, now)
+		if err == nil {
+			t.Errorf("ParseInterval(%q)\nhave %v\nwant parse error", tc, have)
+		}
+	}
+}
+
+func TestParseIntervalBias(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 4, 15, 4, 5, 0, loc) // Wednesday
+	tests := []struct {
+		bias Bias
+		in   string
+		want Interval
+	}{
+		{
+			Future,
+			"Monday to Friday",
+			Interval{
+				time.Date(2006, time.January, 9, 0, 0, 0, 0, loc),
+				time.Date(2006, time.January, 13, 0, 0, 0, 0, loc),
+			},
+		},
+		{
+			Future,
+			"Friday to Monday",
+			Interval{
+				time.Date(2006, time.January, 6, 0, 0, 0, 0, loc),
+				time.Date(2006, time.January, 9, 0, 0, 0, 0, loc),
+			},
+		},
+		{
+			Past,
+			"Monday through Friday",
+			Interval{
+				time.Date(2005, time.December, 26, 0, 0, 0, 0, loc),
+				time.Date(2005, time.December, 31, 0, 0, 0, 0, loc),
+			},
+		},
+		{
+			Past,
+			"from Friday to Monday",
+			Interval{
+				time.Date(2005, time.December, 30, 0, 0, 0, 0, loc),
+				time.Date(2006, time.January, 2, 0, 0, 0, 0, loc),
+			},
+		},
+		{
+			Past,
+			"between December 20th and January 3rd",
+			Interval{
+				time.Date(2005, time.December, 20, 0, 0, 0, 0, loc),
+				time.Date(2006, time.January, 3, 0, 0, 0, 0, loc),
+			},
+		},
+	}
+	for _, tt := range tests {
+		have, err := New(WithBias(tt.bias)).ParseIntervalNow(tt.in, now)
+		if err != nil {
+			t.Fatalf("ParseInterval(%q) %v", tt.in, err)
+		}
+		if !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("ParseInterval(%q)\nhave %v\nwant %v", tt.in, have, tt.want)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package when
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// timestamp is an ISO 8601 date with an optional time of day and zone.
+type timestamp struct {
+	date  Date
+	clock *Clock
+	loc   *time.Location
+	plain bool // the date is in the extended calendar form 2006-01-02
+}
+
+// timestampLen returns the length of the ISO 8601 timestamp at the start
+// of s, or zero if there is none. Dates in the extended calendar form
+// without a time of day, such as 2006-01-02, are left to the date tokens.
+// Invalid timestamps that could be nothing else, such as 2006-W54, are
+// included so the parser reports why they are invalid.
+func timestampLen(s string) int {
+	n := timestampSyntaxLen(s)
+	if n == 0 {
+		return 0
+	}
+	ts, err := parseTimestamp(s[:n])
+	if err == nil {
+		if ts.plain {
+			return 0
+		}
+		return n
+	}
+	rest := s[4:n]
+	if _, ok := digits(rest[1:], 3); strings.ContainsAny(rest, "TtWw") || len(rest) == 4 && rest[0] == '-' && ok {
+		return n
+	}
+	return 0
+}
+
+// timestampSyntaxLen returns the length of the text at the start of s
+// written in the form of an ISO 8601 timestamp, or zero if there is none.
+// The form of each field is followed, but the number of digits and the
+// values are left to parseTimestamp, so the timestamp ends before any
+// trailing punctuation.
+func timestampSyntaxLen(s string) int {
+	i := 0
+	run := func() int {
+		j := i
+		for j < len(s) && isDigit(s[j]) {
+			j++
+		}
+		n := j - i
+		i = j
+		return n
+	}
+	// next reports whether s continues with one of the bytes of seps
+	// followed by a digit, and skips the byte if so.
+	next := func(seps string) bool {
+		if i+1 < len(s) && strings.IndexByte(seps, s[i]) >= 0 && isDigit(s[i+1]) {
+			i++
+			return true
+		}
+		return false
+	}
+	switch n := run(); {
+	case n == 4 && i+1 < len(s) && s[i] == '-' && (s[i+1] == 'W' || s[i+1] == 'w'):
+		i++
+		if !next("Ww") {
+			return 0
+		}
+		run()
+		if next("-") {
+			run()
+		}
+	case n == 4 && next("-"):
+		switch run() {
+		case 2:
+			if !next("-") {
+				return 0
+			}
+			run()
+		case 3:
+		default:
+			return 0
+		}
+	case n == 4 && next("Ww"):
+		run()
+	case n == 7 || n == 8:
+	default:
+		return 0
+	}
+	if !next("Tt") {
+		return i
+	}
+	run()
+	for next(":") {
+		run()
+	}
+	if next(".,") {
+		run()
+	}
+	switch {
+	case i < len(s) && (s[i] == 'Z' || s[i] == 'z'):
+		i++
+	case i < len(s) && (s[i] == '+' || s[i] == '-'):
+		n, _ := zoneOffset(s[i:])
+		i += n
+	}
+	return i
+}
+
+// parseTimestamp parses an ISO 8601 timestamp in the extended or basic
+// format. The date is a calendar date (2006-01-02), a week date
+// (2006-W01-2) or an ordinal date (2006-002), optionally followed by "T",
+// a time of day with fractional seconds and a zone.
+func parseTimestamp(s string) (timestamp, error) {
+	var ts timestamp
+	date, clock := s, ""
+	if i := strings.IndexAny(s, "Tt"); i >= 0 {
+		date, clock = s[:i], s[i+1:]
+		if clock == "" {
+			return ts, errors.New("missing time of day")
+		}
+	}
+	y, ok := digits(date, 4)
+	if !ok {
+		return ts, errors.New("invalid year")
+	}
+	date = date[4:]
+	ext := strings.HasPrefix(date, "-")
+	if ext {
+		date = date[1:]
+	}
+	switch {
+	case strings.HasPrefix(date, "W") || strings.HasPrefix(date, "w"):
+		w, ok := digits(date[1:], 2)
+		// December 28th is always in the last week of the ISO year.
+		_, weeks := time.Date(y, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+		if !ok || w < 1 || w > weeks {
+			return ts, errors.New("invalid week")
+		}
+		n := &WeekDate{Year: y, Week: w}
+		date = date[3:]
+		if ext && date != "" {
+			if date[0] != '-' {
+				return ts, errors.New("invalid week day")
+			}
+			date = date[1:]
+		}
+		if date != "" {
+			d, ok := digits(date, 1)
+			if !ok || len(date) != 1 || d < 1 || d > 7 {
+				return ts, errors.New("invalid week day")
+			}
+			n.Day = d
+		}
+		ts.date = n
+	case len(date) == 3:
+		d, ok := digits(date, 3)
+		if !ok {
+			return ts, errors.New("invalid ordinal date")
+		}
+		t := time.Date(y, time.January, d, 0, 0, 0, 0, time.UTC)
+		if d < 1 || t.Year() != y {
+			return ts, errors.New("ordinal date out of range")
+		}
+		ts.date = &CalendarDate{Year: y, Month: t.Month(), Day: t.Day()}
+	default:
+		if ext {
+			if len(date) != 5 || date[2] != '-' {
+				return ts, errors.New("invalid date")
+			}
+			date = date[:2] + date[3:]
+		}
+		M, ok := digits(date, 2)
+		if !ok || len(date) != 4 {
+			return ts, errors.New("invalid date")
+		}
+		d, ok := digits(date[2:], 2)
+		if !ok {
+			return ts, errors.New("invalid date")
+		}
+		t := time.Date(y, time.Month(M), d, 0, 0, 0, 0, time.UTC)
+		if t.Month() != time.Month(M) || t.Day() != d {
+			return ts, errors.New("date out of range")
+		}
+		ts.date = &CalendarDate{Year: y, Month: time.Month(M), Day: d}
+		ts.plain = ext && clock == ""
+	}
+	if clock == "" {
+		return ts, nil
+	}
+	c, loc, err := parseTimestampClock(clock)
+	if err != nil {
+		return ts, err
+	}
+	ts.clock, ts.loc = c, loc
+	return ts, nil
+}
+
+// parseTimestampClock parses the time of day and zone of a timestamp.
+func parseTimestampClock(s string) (*Clock, *time.Location, error) {
+	var loc *time.Location
+	switch i := strings.IndexAny(s, "Zz+-"); {
+	case i < 0:
+	case i == len(s)-1 && (s[i] == 'Z' || s[i] == 'z'):
+		loc = time.UTC
+		s = s[:i]
+	default:
+		n, offset := zoneOffset(s[i:])
+		if n == 0 || i+n != len(s) {
+			return nil, nil, errors.New("invalid time zone offset")
+		}
+		loc = time.FixedZone(formatOffset(offset), offset)
+		s = s[:i]
+	}
+	c := &Clock{Precision: Hour}
+	fields := []*int{&c.Hour, &c.Minute, &c.Second}
+	limits := []int{23, 59, 59}
+	for i, f := range fields {
+		if i > 0 {
+			if s == "" || s[0] == '.' || s[0] == ',' {
+				break
+			}
+			if s[0] == ':' {
+				s = s[1:]
+			}
+			c.Precision++
+		}
+		n, ok := digits(s, 2)
+		if !ok || n > limits[i] {
+			return nil, nil, errors.New("invalid time of day")
+		}
+		*f = n
+		s = s[2:]
+	}
+	if s != "" {
+		if c.Precision != Second || s[0] != '.' && s[0] != ',' || len(s) < 2 {
+			return nil, nil, errors.New("invalid time of day")
+		}
+		for i := 1; i < len(s); i++ {
+			if !isDigit(s[i]) {
+				return nil, nil, errors.New("invalid fractional second")
+			}
+		}
+		c.Nanosecond, c.Precision = parseFraction(s[1:])
+	}
+	return c, loc, nil
+}
+
+// durationLen returns the length of the ISO 8601 duration at the start of
+// s, or zero if there is none. The duration is not validated.
+func durationLen(s string) int {
+	if len(s) < 2 || s[0] != 'P' && s[0] != 'p' {
+		return 0
+	}
+	if !isDigit(s[1]) && s[1] != 'T' && s[1] != 't' {
+		return 0
+	}
+	n := 1
+	for n < len(s) && (isDigit(s[n]) || isLetter(s[n]) || s[n] == '.' || s[n] == ',') {
+		n++
+	}
+	return n
+}
+
+// parseDuration parses an ISO 8601 duration such as P1Y2M3DT4H5M6S or
+// P2W into terms, in the order they are written. Units before the "T"
+// are years, months, weeks and days, and units after it are hours,
+// minutes and seconds. The last unit may have a decimal fraction, as in
+// PT1.5H.
+func parseDuration(s string) ([]Term, error) {
+	s = strings.ToUpper(s)
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return nil, errors.New("invalid duration")
+	}
+	units := "YMWD"
+	grans := []Granularity{Year, Month, Week, Day, Hour, Minute, Second}
+	var terms []Term
+	clock := false
+	for i := 1; i < len(s); {
+		if s[i] == 'T' {
+			if clock || i+1 == len(s) {
+				return nil, errors.New("invalid duration")
+			}
+			units, grans = "HMS", grans[len(grans)-3:]
+			clock = true
+			i++
+			continue
+		}
+		j := i
+		for j < len(s) && (isDigit(s[j]) || s[j] == '.' || s[j] == ',') {
+			j++
+		}
+		if j == i || j == len(s) {
+			return nil, errors.New("invalid duration")
+		}
+		k := strings.IndexByte(units, s[j])
+		if k < 0 {
+			return nil, errors.New("invalid duration unit")
+		}
+		v := strings.Replace(s[i:j], ",", ".", 1)
+		num, den, err := parseDecimal(v)
+		if err != nil {
+			return nil, err
+		}
+		if den > 1 && j+1 != len(s) {
+			return nil, errors.New("fraction must be in the last unit of a duration")
+		}
+		terms = append(terms, fractionTerms(num, den, grans[k])...)
+		// Units must be written from largest to smallest.
+		units, grans = units[k+1:], grans[k+1:]
+		i = j + 1
+	}
+	return terms, nil
+}
+
+// digits returns the value of the first n bytes of s if they are all
+// decimal digits.
+func digits(s string, n int) (int, bool) {
+	if len(s) < n {
+		return 0, false
+	}
+	v := 0
+	for i := 0; i < n; i++ {
+		if !isDigit(s[i]) {
+			return 0, false
+		}
+		v = v*10 + int(s[i]-'0')
+	}
+	return v, true
+}
@@ -0,0 +1,182 @@
+package when
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2006-01-02T15:04:05Z", time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)},
+		{"2006-01-02t15:04:05z", time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)},
+		{"2006-01-02T15:04:05.123-07:00", time.Date(2006, time.January, 2, 15, 4, 5, 123000000, loc)},
+		{"2006-01-02T15:04:05,5+05:30", time.Date(2006, time.January, 2, 15, 4, 5, 500000000, time.FixedZone("", 19800))},
+		{"2006-01-02T15:04", time.Date(2006, time.January, 2, 15, 4, 0, 0, loc)},
+		{"2006-01-02T15", time.Date(2006, time.January, 2, 15, 0, 0, 0, loc)},
+		{"20060102T150405Z", time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)},
+		{"20060102T1504-0700", time.Date(2006, time.January, 2, 15, 4, 0, 0, loc)},
+		{"2006-W01-2", time.Date(2006, time.January, 3, 0, 0, 0, 0, loc)},
+		{"2006W017", time.Date(2006, time.January, 8, 0, 0, 0, 0, loc)},
+		{"2006-W01", time.Date(2006, time.January, 2, 0, 0, 0, 0, loc)},
+		{"2009-W53-7", time.Date(2010, time.January, 3, 0, 0, 0, 0, loc)},
+		{"2004-W53-1", time.Date(2004, time.December, 27, 0, 0, 0, 0, loc)},
+		{"2008-W01-1", time.Date(2007, time.December, 31, 0, 0, 0, 0, loc)},
+		{"2006-002", time.Date(2006, time.January, 2, 0, 0, 0, 0, loc)},
+		{"2008-366", time.Date(2008, time.December, 31, 0, 0, 0, 0, loc)},
+		{"2006-032T12:00Z", time.Date(2006, time.February, 1, 12, 0, 0, 0, time.UTC)},
+		{"2024-03-01T10:00:00Z + 2h", time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)},
+		{"2 days before 2006-W01-2T10:00", time.Date(2006, time.January, 1, 10, 0, 0, 0, loc)},
+		{"on 2006-W01-5 at 3pm", time.Date(2006, time.January, 6, 15, 0, 0, 0, loc)},
+		{"2006-01-02T10:00 PST", time.Date(2006, time.January, 2, 10, 0, 0, 0, time.FixedZone("PST", -8*3600))},
+	}
+	for _, tt := range tests {
+		have, err := ParseNow(tt.in, now)
+		if err != nil {
+			t.Errorf("ParseNow(%q)\nunexpected error: %v", tt.in, err)
+			continue
+		}
+		if !have.Equal(tt.want) {
+			t.Errorf("ParseNow(%q)\nhave %v\nwant %v", tt.in, have, tt.want)
+		}
+	}
+}
+
+func TestParseTimestampError(t *testing.T) {
+	tests := []string{
+		"2006-01-02T",
+		"2006-01-02T25:00",
+		"2006-01-02T15:04:05.",
+		"2006-02-30T12:00",
+		"2006-W54-1",
+		"2006-W53",
+		"2006-W53-1",
+		"2006-W01-8",
+		"2006-366",
+		"2006-01-02T15:04Z UTC",
+		"3pm 2006-01-02T15:04",
+	}
+	for _, tt := range tests {
+		have, err := ParseExpr(tt)
+		if err == nil {
+			t.Errorf("ParseExpr(%q)\nhave %v\nwant parse error", tt, have)
+		}
+	}
+}
+
+func TestTimestampLen(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"2006-01-02T15:04:05Z", 20},
+		{"2006-01-02T15:04:05Z, then", 20},
+		{"2006-01-02T15:04:05.", 19},
+		{"2006-01-02T15:04:05.250+07:00.", 29},
+		{"2006-01-02T15:04+", 16},
+		{"2006-01-02T15:04:05-07:00,", 25},
+		{"20060102T150405Z.", 16},
+		{"2006-W01-2,", 10},
+		{"2006-002.", 8},
+		{"2006-W54", 8},
+		{"2006-01-02", 0},
+		{"2006-01-02T", 0},
+		{"2006-01-02Tea", 0},
+		{"2006", 0},
+		{"2006-1T2", 0},
+	}
+	for _, tt := range tests {
+		if have := timestampLen(tt.in); have != tt.want {
+			t.Errorf("timestampLen(%q)\nhave %d\nwant %d", tt.in, have, tt.want)
+		}
+	}
+}
+
+func TestParseTimestampRange(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	tests := []struct {
+		in   string
+		want Range
+	}{
+		{"2006-W02", Range{
+			Start:       time.Date(2006, time.January, 9, 0, 0, 0, 0, loc),
+			End:         time.Date(2006, time.January, 16, 0, 0, 0, 0, loc),
+			Granularity: Week,
+		}},
+		{"2006-W02-3", Range{
+			Start:       time.Date(2006, time.January, 11, 0, 0, 0, 0, loc),
+			End:         time.Date(2006, time.January, 12, 0, 0, 0, 0, loc),
+			Granularity: Day,
+		}},
+	}
+	for _, tt := range tests {
+		have, err := ParseRangeNow(tt.in, now)
+		if err != nil {
+			t.Errorf("ParseRangeNow(%q)\nunexpected error: %v", tt.in, err)
+			continue
+		}
+		if !have.Start.Equal(tt.want.Start) || !have.End.Equal(tt.want.End) || have.Granularity != tt.want.Granularity {
+			t.Errorf("ParseRangeNow(%q)\nhave %v\nwant %v", tt.in, have, tt.want)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"P1Y2M3DT4H5M6S from now", time.Date(2007, time.March, 5, 19, 9, 11, 0, loc)},
+		{"PT90M ago", time.Date(2006, time.January, 2, 13, 34, 5, 0, loc)},
+		{"now + P2W", time.Date(2006, time.January, 16, 15, 4, 5, 0, loc)},
+		{"p1d ago", time.Date(2006, time.January, 1, 15, 4, 5, 0, loc)},
+		{"P1M before tomorrow", time.Date(2005, time.December, 3, 0, 0, 0, 0, loc)},
+		{"1 day + P1W ago", time.Date(2005, time.December, 25, 15, 4, 5, 0, loc)},
+		{"tomorrow at noon - PT1H30M", time.Date(2006, time.January, 3, 10, 30, 0, 0, loc)},
+		{"2024-03-01T10:00:00Z + PT2H", time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		have, err := ParseNow(tt.in, now)
+		if err != nil {
+			t.Errorf("ParseNow(%q)\nunexpected error: %v", tt.in, err)
+			continue
+		}
+		if !have.Equal(tt.want) {
+			t.Errorf("ParseNow(%q)\nhave %v\nwant %v", tt.in, have, tt.want)
+		}
+	}
+}
+
+func TestParseDurationError(t *testing.T) {
+	tests := []string{
+		"PT ago",
+		"P1DT ago",
+		"P1M1Y ago",
+		"P1H ago",
+		"PT1D ago",
+		"P1D1D ago",
+		"now + P",
+	}
+	for _, tt := range tests {
+		have, err := ParseExpr(tt)
+		if err == nil {
+			t.Errorf("ParseExpr(%q)\nhave %v\nwant parse error", tt, have)
+		}
+	}
+}
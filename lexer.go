@@ -1,8 +1,8 @@
 package when
 
 import (
-	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -30,13 +30,74 @@ const (
 	tokenTwelveHour
 	tokenUnit
 	tokenWeekday
+	tokenZone
+	tokenTimestamp
+	tokenDuration
+	tokenHoliday
+	tokenPeriod
 )
 
 const eof = rune(-1)
 
+func (t tokenType) String() string {
+	switch t {
+	case tokenAgo:
+		return "ago"
+	case tokenBefore:
+		return "before"
+	case tokenColon:
+		return "colon"
+	case tokenDate:
+		return "date"
+	case tokenDateSeparator:
+		return "date separator"
+	case tokenDigit:
+		return "digit"
+	case tokenEOF:
+		return "end of input"
+	case tokenError:
+		return "error"
+	case tokenFrom:
+		return "from"
+	case tokenKeyword:
+		return "keyword"
+	case tokenMonth:
+		return "month"
+	case tokenNow:
+		return "now"
+	case tokenOperatorAdd:
+		return "addition operator"
+	case tokenOperatorSub:
+		return "subtraction operator"
+	case tokenOrdinal:
+		return "ordinal"
+	case tokenTime:
+		return "time"
+	case tokenTwelveHour:
+		return "am/pm"
+	case tokenUnit:
+		return "unit"
+	case tokenWeekday:
+		return "weekday"
+	case tokenZone:
+		return "time zone"
+	case tokenTimestamp:
+		return "timestamp"
+	case tokenDuration:
+		return "duration"
+	case tokenHoliday:
+		return "holiday"
+	case tokenPeriod:
+		return "period"
+	}
+	return fmt.Sprintf("tokenType(%d)", int(t))
+}
+
 type token struct {
 	typ tokenType
 	val string
+	pos int // byte offset of the token within input
+	end int // byte offset just past the token
 }
 
 func (t token) String() string {
@@ -52,41 +113,109 @@ func (t token) String() string {
 type stateFn func(*lexer) stateFn
 
 type lexer struct {
-	input  string
-	i, j   int // position within input
-	width  int // width of last rune
-	tokens []token
+	input    string
+	i, j     int // position within input
+	width    int // width of last rune
+	tokens   []token
+	holidays []holidayName
+	locale   *Locale
+	skip     bool  // skip words that cannot be read instead of failing
+	gaps     []int // indexes of the tokens following skipped words
 }
 
-func lex(s string) ([]token, error) {
+// holidayName is the name of a holiday and its holiday key.
+type holidayName struct {
+	name string
+	key  string
+}
+
+// lexer returns a lexer of s recognizing the holidays and locale of the
+// parser.
+func (p *Parser) lexer(s string) *lexer {
 	l := &lexer{
 		input:  s,
 		tokens: make([]token, 0),
+		locale: p.locale,
+	}
+	if p.holidays != nil {
+		for _, name := range p.holidays.Names() {
+			l.holidays = append(l.holidays, holidayName{name, holidayKey(name)})
+		}
 	}
+	return l
+}
+
+func lex(s string) ([]token, error) {
+	return defaultParser.lex(s)
+}
+
+func (p *Parser) lex(s string) ([]token, error) {
+	l := p.lexer(s)
 	for state := readExpr; state != nil; {
 		state = state(l)
 	}
 	if len(l.tokens) > 0 {
 		last := l.tokens[len(l.tokens)-1]
 		if last.typ == tokenError {
-			return nil, errors.New(last.val)
+			return nil, l.error(last)
 		}
 	}
 	return l.tokens, nil
 }
 
+// error returns the parse error described by the error token t.
+func (l *lexer) error(t token) *ParseError {
+	return &ParseError{
+		Input:   l.input,
+		Pos:     t.pos,
+		End:     t.end,
+		Value:   l.input[t.pos:t.end],
+		Message: t.val,
+	}
+}
+
 func (l *lexer) emit(typ tokenType) {
 	v := l.value()
 	l.emitAs(typ, v)
 }
 
 func (l *lexer) emitAs(typ tokenType, value string) {
-	l.tokens = append(l.tokens, token{typ, value})
+	l.tokens = append(l.tokens, token{typ, value, l.i, l.j})
 	l.i = l.j
 }
 
+// errorf emits an error token spanning the pending input, or the
+// next rune if nothing is pending, and terminates the scan. When words
+// are skipped, the rest of the word, or a lone mark of punctuation, is
+// skipped instead and the scan continues after it.
 func (l *lexer) errorf(format string, args ...interface{}) stateFn {
-	l.tokens = append(l.tokens, token{tokenError, fmt.Sprintf(format, args...)})
+	end := l.j
+	if end <= l.i && l.i < len(l.input) {
+		_, width := utf8.DecodeRuneInString(l.input[l.i:])
+		end = l.i + width
+	}
+	if l.skip {
+		// A mark of punctuation is skipped alone, so the word it is
+		// attached to is still read, as in "(tomorrow)".
+		r, width := utf8.DecodeRuneInString(l.input[l.i:])
+		if end == l.i+width && (unicode.IsPunct(r) || unicode.IsSymbol(r)) {
+			l.gaps = append(l.gaps, len(l.tokens))
+			l.i, l.j = end, end
+			return readExpr
+		}
+		for end < len(l.input) {
+			r, width := utf8.DecodeRuneInString(l.input[end:])
+			if unicode.IsSpace(r) {
+				break
+			}
+			end += width
+		}
+		l.gaps = append(l.gaps, len(l.tokens))
+		l.i, l.j = end, end
+		return readExpr
+	}
+	v := fmt.Sprintf(format, args...)
+	l.tokens = append(l.tokens, token{tokenError, v, l.i, end})
 	return nil
 }
 
@@ -131,6 +260,117 @@ func (l *lexer) readString(s string) bool {
 	return unicode.IsSpace(r) || r == eof
 }
 
+// readWord reads the first of words found at the current position that
+// is not followed by a letter.
+func (l *lexer) readWord(words ...string) bool {
+	s := l.input[l.j:]
+	for _, w := range words {
+		if !strings.HasPrefix(s, w) {
+			continue
+		}
+		r, _ := utf8.DecodeRuneInString(s[len(w):])
+		if !unicode.IsLetter(r) {
+			l.j += len(w)
+			return true
+		}
+	}
+	return false
+}
+
+// holiday returns the longest holiday name at the current position and
+// its length.
+func (l *lexer) holiday() (string, int) {
+	name, n := "", 0
+	for _, h := range l.holidays {
+		if i := holidayLen(l.input[l.i:], h.key); i > n {
+			name, n = h.name, i
+		}
+	}
+	return name, n
+}
+
+// readPeriod reads a quarter, half or fiscal year, as in "Q3", "H1",
+// "FY2026" or "FY26", and returns it in upper case with a four digit
+// year.
+func (l *lexer) readPeriod() string {
+	s := l.input[l.j:]
+	n, v := 0, ""
+	switch {
+	case len(s) >= 2 && (s[0] == 'Q' || s[0] == 'q') && s[1] >= '1' && s[1] <= '4':
+		n, v = 2, "Q"+s[1:2]
+	case len(s) >= 2 && (s[0] == 'H' || s[0] == 'h') && (s[1] == '1' || s[1] == '2'):
+		n, v = 2, "H"+s[1:2]
+	case len(s) >= 4 && strings.EqualFold(s[:2], "FY"):
+		if _, ok := digits(s[2:], 4); ok {
+			n, v = 6, "FY"+s[2:6]
+		} else if y, ok := digits(s[2:], 2); ok {
+			n, v = 4, "FY"+strconv.Itoa(2000+y)
+		}
+	}
+	if n == 0 || n < len(s) && (isDigit(s[n]) || isLetter(s[n])) {
+		return ""
+	}
+	l.j += n
+	return v
+}
+
+// readWorkingUnit reads the day or hour unit following "business" or
+// "working", as in "business days", and returns it in lower case.
+func (l *lexer) readWorkingUnit() string {
+	j := l.j
+	l.readFn(unicode.IsSpace)
+	if l.j > j {
+		s := strings.ToLower(l.peekFn(unicode.IsLetter))
+		switch s {
+		case "day", "days", "hour", "hours":
+			l.j += len(s)
+			return s
+		}
+	}
+	l.j = j
+	return ""
+}
+
+// readLocaleOrdinal reads an ordinal suffix of the locale following the
+// digits just emitted, as in "3." or "3º", and emits it as the English
+// suffix of the number.
+func (l *lexer) readLocaleOrdinal() bool {
+	if l.locale == nil {
+		return false
+	}
+	s := l.input[l.j:]
+	n := l.locale.ordinal(s)
+	if n == 0 || n < len(s) && isDigit(s[n]) {
+		return false
+	}
+	d, err := strconv.Atoi(l.tokens[len(l.tokens)-1].val)
+	if err != nil {
+		return false
+	}
+	l.j += n
+	l.emitAs(tokenOrdinal, strings.TrimLeft(ordinal(d), "0123456789"))
+	return true
+}
+
+// readFraction reads a decimal point followed by digits, or a slash
+// followed by digits and then a unit, as in "1/2 hour", if present.
+func (l *lexer) readFraction() {
+	s := l.input[l.j:]
+	switch {
+	case len(s) > 1 && s[0] == '.' && isDigit(s[1]):
+		l.j++
+		l.readFn(unicode.IsDigit)
+	case len(s) > 1 && s[0] == '/' && isDigit(s[1]):
+		n := 1
+		for n < len(s) && isDigit(s[n]) {
+			n++
+		}
+		if l.unitFollows(l.j + n) {
+			l.j += n
+		}
+	}
+}
+
 func (l *lexer) peek() rune {
 	r := l.read()
 	l.unread()
@@ -173,6 +413,8 @@ func readExpr(l *lexer) stateFn {
 		return nil
 	case r == '@':
 		return readAtSymbol
+	case (r == '+' || r == '-') && l.afterClock():
+		return readZoneOffset
 	case r == '+':
 		l.read()
 		l.emit(tokenOperatorAdd)
@@ -182,7 +424,16 @@ func readExpr(l *lexer) stateFn {
 		l.emit(tokenOperatorSub)
 		return readExpr
 	case unicode.IsDigit(r):
+		if n := timestampLen(l.input[l.j:]); n > 0 {
+			l.j += n
+			l.emit(tokenTimestamp)
+			return readExpr
+		}
 		return readDigit
+	case (r == 'P' || r == 'p') && durationLen(l.input[l.j:]) > 0:
+		l.j += durationLen(l.input[l.j:])
+		l.emit(tokenDuration)
+		return readDurationNext
 	case unicode.IsLetter(r):
 		return readLetter
 	}
@@ -210,7 +461,21 @@ func readColon(l *lexer) stateFn {
 
 func readDigit(l *lexer) stateFn {
 	l.readFn(unicode.IsDigit)
+	if l.peek() == '.' && (dottedDateLen(l.input[l.i:]) > 0 || l.afterDot()) {
+		l.emit(tokenDigit)
+		l.read()
+		l.emit(tokenDateSeparator)
+		return readDigit
+	}
+	l.readFraction()
 	l.emit(tokenDigit)
+	if l.readLocaleOrdinal() {
+		return readExpr
+	}
+	if l.readWord("ms", "us", "µs", "ns") {
+		l.emit(tokenUnit)
+		return readDurationNextShort
+	}
 	r := l.peek()
 	switch r {
 	case eof:
@@ -310,50 +575,38 @@ func readDurationSpace(l *lexer) stateFn {
 }
 
 func readDurationSpaceNext(l *lexer) stateFn {
-	space := l.value()
+	w := l.peekFn(unicode.IsLetter)
+	v := strings.ToLower(w)
+	if l.locale != nil {
+		if _, ok := l.locale.Numbers[v]; ok {
+			l.emit(tokenOperatorAdd)
+			return readExpr
+		}
+		if k, ok := l.locale.Keywords[v]; ok {
+			v = k
+		}
+	}
+	if n, _, _ := parseNumberWords(l.input[l.j:]); n > 0 || v == "a" || v == "an" || v == "half" {
+		l.emit(tokenOperatorAdd)
+		return readExpr
+	}
 	l.ignore()
-	v := l.peekFn(unicode.IsLetter)
-	v = strings.ToLower(v)
 	switch v {
 	case "ago":
-		l.readString("ago")
-		l.emit(tokenAgo)
+		l.readString(w)
+		l.emitAs(tokenAgo, v)
 	case "before":
-		l.readString("before")
-		l.emit(tokenBefore)
+		l.readString(w)
+		l.emitAs(tokenBefore, v)
 	case "after":
-		l.readString("after")
-		l.emit(tokenFrom)
+		l.readString(w)
+		l.emitAs(tokenFrom, v)
 	case "from":
-		l.readString("from")
-		l.emit(tokenFrom)
+		l.readString(w)
+		l.emitAs(tokenFrom, v)
 	case "and":
-		l.readString("and")
-		l.emit(tokenOperatorAdd)
-	case "one", "a":
-		fallthrough
-	case "two":
-		fallthrough
-	case "three":
-		fallthrough
-	case "four":
-		fallthrough
-	case "five":
-		fallthrough
-	case "six":
-		fallthrough
-	case "seven":
-		fallthrough
-	case "eight":
-		fallthrough
-	case "nine":
-		fallthrough
-	case "ten":
-		fallthrough
-	case "eleven":
-		fallthrough
-	case "twelve":
-		l.emitAs(tokenOperatorAdd, space)
+		l.readString(w)
+		l.emitAs(tokenOperatorAdd, v)
 	}
 	return readExpr
 }
@@ -371,57 +624,72 @@ func readDurationUnitSecondsOrOrdinal(l *lexer) stateFn {
 }
 
 func readLetter(l *lexer) stateFn {
-	l.readFn(isTimeRune)
-	v := l.value()
-	v = strings.ToLower(v)
-	switch v {
-	case "now":
-		l.emit(tokenNow)
-		return readExpr
-	case "today", "tomorrow", "yesterday":
-		l.emit(tokenDate)
-		return readExpr
-	case "midnight", "noon":
-		l.emit(tokenTime)
-		return readExpr
-	case "one", "a":
-		l.emitAs(tokenDigit, "1")
-		return readExpr
-	case "two":
-		l.emitAs(tokenDigit, "2")
-		return readExpr
-	case "three":
-		l.emitAs(tokenDigit, "3")
+	if v := l.readPeriod(); v != "" {
+		l.emitAs(tokenPeriod, v)
 		return readExpr
-	case "four":
-		l.emitAs(tokenDigit, "4")
+	}
+	if name, n := l.holiday(); n > 0 {
+		l.j = l.i + n
+		l.emitAs(tokenHoliday, name)
 		return readExpr
-	case "five":
-		l.emitAs(tokenDigit, "5")
+	}
+	if n, v, ord := parseNumberWords(l.input[l.i:]); n > 0 {
+		l.j = l.i + n
+		if !ord {
+			l.emitAs(tokenDigit, strconv.Itoa(v))
+			return readExpr
+		}
+		// The suffix spans the ordinal word, as "first" in "thirty first".
+		w := strings.LastIndexFunc(l.value(), func(r rune) bool {
+			return !unicode.IsLetter(r)
+		}) + 1
+		l.tokens = append(l.tokens, token{tokenDigit, strconv.Itoa(v), l.i, l.j})
+		l.i += w
+		l.emitAs(tokenOrdinal, strings.TrimLeft(ordinal(v), "0123456789"))
 		return readExpr
-	case "six":
-		l.emitAs(tokenDigit, "6")
+	}
+	l.readFn(isTimeRune)
+	s := l.value()
+	v := strings.ToLower(s)
+	if l.locale != nil {
+		if state := l.readLocaleWord(v); state != nil {
+			return state
+		}
+		if k, ok := l.locale.Keywords[v]; ok && !l.english(v) {
+			// The English word is read in its place.
+			s, v = k, k
+		}
+	}
+	switch v {
+	case "":
+		l.ignore()
 		return readExpr
-	case "seven":
-		l.emitAs(tokenDigit, "7")
+	case "now":
+		l.emitAs(tokenNow, s)
 		return readExpr
-	case "eight":
-		l.emitAs(tokenDigit, "8")
+	case "ago":
+		if l.locale == nil || !l.locale.Order.AgoFirst {
+			break
+		}
+		l.emitAs(tokenAgo, s)
 		return readExpr
-	case "nine":
-		l.emitAs(tokenDigit, "9")
+	case "from":
+		if len(l.tokens) > 0 && !l.afterDate() {
+			break
+		}
+		l.emitAs(tokenFrom, s)
 		return readExpr
-	case "ten":
-		l.emitAs(tokenDigit, "10")
+	case "today", "tomorrow", "yesterday":
+		l.emitAs(tokenDate, s)
 		return readExpr
-	case "eleven":
-		l.emitAs(tokenDigit, "11")
+	case "midnight", "noon":
+		l.emitAs(tokenTime, s)
 		return readExpr
-	case "twelve":
-		l.emitAs(tokenDigit, "12")
+	case "a", "an":
+		l.emitAs(tokenDigit, "1")
 		return readExpr
 	case "am", "pm":
-		l.emit(tokenTwelveHour)
+		l.emitAs(tokenTwelveHour, s)
 		return readExpr
 	case "year", "years":
 		fallthrough
@@ -436,8 +704,21 @@ func readLetter(l *lexer) stateFn {
 	case "minute", "minutes":
 		fallthrough
 	case "second", "seconds":
-		l.emit(tokenUnit)
+		fallthrough
+	case "ms", "millis", "millisecond", "milliseconds":
+		fallthrough
+	case "us", "µs", "microsecond", "microseconds":
+		fallthrough
+	case "ns", "nanosecond", "nanoseconds":
+		fallthrough
+	case "workday", "workdays":
+		l.emitAs(tokenUnit, s)
 		return readDurationNext
+	case "business", "working":
+		if unit := l.readWorkingUnit(); unit != "" {
+			l.emitAs(tokenUnit, v+" "+unit)
+			return readDurationNext
+		}
 	case "sun", "sunday":
 		fallthrough
 	case "mon", "monday":
@@ -451,7 +732,7 @@ func readLetter(l *lexer) stateFn {
 	case "fri", "friday":
 		fallthrough
 	case "sat", "saturday":
-		l.emit(tokenWeekday)
+		l.emitAs(tokenWeekday, s)
 		return readExpr
 	case "jan", "january":
 		fallthrough
@@ -476,19 +757,154 @@ func readLetter(l *lexer) stateFn {
 	case "nov", "november":
 		fallthrough
 	case "dec", "december":
-		l.emit(tokenMonth)
+		l.emitAs(tokenMonth, s)
 		return readExpr
 	case "in", "of", "on", "the", "next", "last", "upcoming":
 		fallthrough
-	case "at", "quarter", "half", "past", "to", "after":
+	case "this", "previous", "coming":
+		fallthrough
+	case "half":
+		l.emitAs(tokenKeyword, v)
+		if l.halfOfUnit() {
+			// The duration continues, as in "an hour and a half ago".
+			return readDurationNext
+		}
+		return readExpr
+	case "at", "quarter", "quarters", "past", "to", "after":
+		fallthrough
+	case "between", "and", "until", "through":
+		fallthrough
+	case "every", "other", "start", "beginning", "end":
+		fallthrough
+	case "eod", "eow", "eom", "eoq", "eoy":
 		fallthrough
 	case "oclock", "o'clock", "morning", "afternoon", "evening":
-		l.emit(tokenKeyword)
+		l.emitAs(tokenKeyword, v)
+		return readExpr
+	}
+	if l.peek() == '/' {
+		return readZoneName
+	}
+	if isZone(v) {
+		l.emit(tokenZone)
 		return readExpr
 	}
 	return l.errorf("invalid character")
 }
 
+// afterDate reports whether the tokens emitted so far end in a date, as
+// "Friday" does in "Friday from 3pm to 5pm".
+func (l *lexer) afterDate() bool {
+	n := len(l.tokens)
+	switch l.tokens[n-1].typ {
+	case tokenDate, tokenWeekday, tokenMonth, tokenOrdinal, tokenHoliday, tokenPeriod:
+		return true
+	case tokenDigit:
+		return n > 1 && l.tokens[n-2].typ == tokenDateSeparator
+	}
+	return false
+}
+
+// halfOfUnit reports whether the half just emitted ends "and a half"
+// following a unit, as in "an hour and a half".
+func (l *lexer) halfOfUnit() bool {
+	n := len(l.tokens)
+	if n < 4 || l.tokens[n-4].typ != tokenUnit {
+		return false
+	}
+	a, d := l.tokens[n-3], l.tokens[n-2]
+	return a.val == "and" && d.typ == tokenDigit && d.val == "1"
+}
+
+// english reports whether v, a word of the locale that is also an
+// English word, is read in English where it stands, as "am" following a
+// number or "a" preceding a unit.
+func (l *lexer) english(v string) bool {
+	switch v {
+	case "am", "pm":
+		n := len(l.tokens)
+		return n > 0 && l.tokens[n-1].typ == tokenDigit
+	case "a", "an":
+		return l.unitFollows(l.j)
+	}
+	return false
+}
+
+// readLocaleWord emits the month, weekday, number or unit of the locale
+// named by the lower case word v, returning nil if v is none of them.
+func (l *lexer) readLocaleWord(v string) stateFn {
+	if M, ok := l.locale.Months[v]; ok {
+		l.emitAs(tokenMonth, M.String())
+		return readExpr
+	}
+	if w, ok := l.locale.Weekdays[v]; ok {
+		l.emitAs(tokenWeekday, w.String())
+		return readExpr
+	}
+	if n, ok := l.locale.Numbers[v]; ok {
+		l.emitAs(tokenDigit, strconv.Itoa(n))
+		return readExpr
+	}
+	if u, ok := l.locale.Units[v]; ok {
+		l.emitAs(tokenUnit, u)
+		return readDurationNext
+	}
+	return nil
+}
+
+// readZoneOffset reads a numeric time zone offset such as +05:30
+// following a time of day, or else an operator. An offset of hours alone
+// followed by a unit is a duration, as in "3pm +10 hours".
+func readZoneOffset(l *lexer) stateFn {
+	n, _ := zoneOffset(l.input[l.j:])
+	if n == 3 && l.unitFollows(l.j+n) {
+		n = 0
+	}
+	if n == 0 || l.j == 0 || !unicode.IsSpace(rune(l.input[l.j-1])) {
+		l.read()
+		if l.value() == "+" {
+			l.emit(tokenOperatorAdd)
+		} else {
+			l.emit(tokenOperatorSub)
+		}
+		return readExpr
+	}
+	l.j += n
+	l.emit(tokenZone)
+	return readExpr
+}
+
+// unitFollows reports whether the word following any space at byte
+// offset i is a unit of duration.
+func (l *lexer) unitFollows(i int) bool {
+	s := strings.TrimLeftFunc(l.input[i:], unicode.IsSpace)
+	w := s[:len(s)-len(strings.TrimLeftFunc(s, unicode.IsLetter))]
+	v := strings.ToLower(w)
+	if l.locale != nil {
+		if _, ok := l.locale.Units[v]; ok {
+			return true
+		}
+	}
+	switch v {
+	case "":
+		return false
+	case "s", "second", "seconds", "business", "working":
+		return true
+	}
+	return unitGranularity(w) != Second
+}
+
+// readZoneName reads an IANA time zone name such as Europe/London.
+func readZoneName(l *lexer) stateFn {
+	l.readFn(isZoneRune)
+	_, err := loadZone(l.value())
+	if err != nil {
+		return l.errorf("unknown time zone")
+	}
+	l.emit(tokenZone)
+	return readExpr
+}
+
 func readOrdinal(l *lexer) stateFn {
 	var ok bool
 	r := l.read()
@@ -506,13 +922,70 @@ func readOrdinal(l *lexer) stateFn {
 }
 
 func readTwelveHour(l *lexer) stateFn {
-	if !l.readString("am") && !l.readString("pm") {
+	v := l.peekFn(unicode.IsLetter)
+	v = strings.ToLower(v)
+	if v != "am" && v != "pm" {
 		return l.errorf("expected twelve hour am/pm marker")
 	}
+	l.readFn(unicode.IsLetter)
 	l.emit(tokenTwelveHour)
 	return readExpr
 }
 
+// afterDot reports whether the last token separates the fields of a date
+// with a dot, as in "02.01.2006".
+func (l *lexer) afterDot() bool {
+	n := len(l.tokens)
+	return n > 0 && l.tokens[n-1].typ == tokenDateSeparator && l.tokens[n-1].val == "."
+}
+
+// dottedDateLen returns the length of the date of three numbers separated
+// by dots at the start of s, as in "02.01.2006", or zero if there is none.
+// Two numbers separated by a dot are a decimal number.
+func dottedDateLen(s string) int {
+	i := 0
+	for field := 0; field < 3; field++ {
+		j := i
+		for j < len(s) && isDigit(s[j]) {
+			j++
+		}
+		if j == i || j-i > 4 {
+			return 0
+		}
+		i = j
+		if field < 2 {
+			if i == len(s) || s[i] != '.' {
+				return 0
+			}
+			i++
+		}
+	}
+	return i
+}
+
+// afterClock reports whether the last token ends a time of day.
+func (l *lexer) afterClock() bool {
+	n := len(l.tokens)
+	if n == 0 {
+		return false
+	}
+	switch l.tokens[n-1].typ {
+	case tokenTwelveHour, tokenTime, tokenTimestamp:
+		return true
+	case tokenDigit:
+		return n > 1 && l.tokens[n-2].typ == tokenColon
+	}
+	return false
+}
+
+func isZoneRune(r rune) bool {
+	switch r {
+	case '/', '_', '-', '+':
+		return true
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
 func isTimeRune(r rune) bool {
 	switch r {
 	case '\'':
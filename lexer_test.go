@@ -6,38 +6,52 @@ import (
 	"time"
 )
 
+// lexeme is a token without its position within the input.
+type lexeme struct {
+	typ tokenType
+	val string
+}
+
+func lexemes(tokens []token) []lexeme {
+	v := make([]lexeme, len(tokens))
+	for i, t := range tokens {
+		v[i] = lexeme{t.typ, t.val}
+	}
+	return v
+}
+
 func TestLexer(t *testing.T) {
 	tests := []struct {
 		in   string
-		want []token
+		want []lexeme
 	}{
 		// expr
 		{
 			"",
-			[]token{},
+			[]lexeme{},
 		},
 		{
 			"   ",
-			[]token{},
+			[]lexeme{},
 		},
 		// lhs (words)
 		{
 			"a year",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 			},
 		},
 		{
 			"one year",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 			},
 		},
 		{
 			"one year two months",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenOperatorAdd, " "},
@@ -47,7 +61,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"one year and two months",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenOperatorAdd, "and"},
@@ -57,7 +71,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"one year & two months",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenOperatorAdd, "&"},
@@ -67,7 +81,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"one year, two months",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenOperatorAdd, ","},
@@ -77,7 +91,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"one year + two months",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenOperatorAdd, "+"},
@@ -87,7 +101,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"one year - two months",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenOperatorSub, "-"},
@@ -97,7 +111,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"one year two months three weeks four days five hours six minutes seven seconds",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenOperatorAdd, " "},
@@ -122,7 +136,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"one year two months and three weeks & four days, five hours + six minutes - seven seconds",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenOperatorAdd, " "},
@@ -147,7 +161,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"one year ago",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenAgo, "ago"},
@@ -155,7 +169,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"one year before now",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenBefore, "before"},
@@ -164,7 +178,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"one year after now",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenFrom, "after"},
@@ -173,24 +187,36 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"one year from now",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenFrom, "from"},
 				{tokenNow, "now"},
 			},
 		},
+		{
+			"Friday from 3pm to 5pm",
+			[]lexeme{
+				{tokenWeekday, "Friday"},
+				{tokenFrom, "from"},
+				{tokenDigit, "3"},
+				{tokenTwelveHour, "pm"},
+				{tokenKeyword, "to"},
+				{tokenDigit, "5"},
+				{tokenTwelveHour, "pm"},
+			},
+		},
 		// lhs (digit, long unit)
 		{
 			"1 year",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 			},
 		},
 		{
 			"1 year 2 months",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenOperatorAdd, " "},
@@ -200,7 +226,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1 year and 2 months",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenOperatorAdd, "and"},
@@ -210,7 +236,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1 year & 2 months",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenOperatorAdd, "&"},
@@ -220,7 +246,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1 year, 2 months",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenOperatorAdd, ","},
@@ -230,7 +256,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1 year + 2 months",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenOperatorAdd, "+"},
@@ -240,7 +266,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1 year - 2 months",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenOperatorSub, "-"},
@@ -250,7 +276,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1 year 2 months 3 weeks 4 days 5 hours 6 minutes 7 seconds",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenOperatorAdd, " "},
@@ -275,7 +301,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1 year 2 months and 3 weeks & 4 days, 5 hours + 6 minutes - 7 seconds",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenOperatorAdd, " "},
@@ -300,7 +326,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1 year 2 months and 3 weeks&4 days,5 hours+6 minutes-7 seconds ",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenOperatorAdd, " "},
@@ -325,7 +351,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1 year ago",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenAgo, "ago"},
@@ -333,7 +359,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1 year before now",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenBefore, "before"},
@@ -342,7 +368,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1 year after now",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenFrom, "after"},
@@ -351,7 +377,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1 year from now",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "year"},
 				{tokenFrom, "from"},
@@ -361,14 +387,14 @@ func TestLexer(t *testing.T) {
 		// lhs (digit, short unit)
 		{
 			"1y",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "y"},
 			},
 		},
 		{
 			"1y 2M",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "y"},
 				{tokenOperatorAdd, " "},
@@ -378,7 +404,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1y and 2M",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "y"},
 				{tokenOperatorAdd, "and"},
@@ -388,7 +414,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1y & 2M",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "y"},
 				{tokenOperatorAdd, "&"},
@@ -398,7 +424,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1y, 2M",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "y"},
 				{tokenOperatorAdd, ","},
@@ -408,7 +434,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1y + 2M",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "y"},
 				{tokenOperatorAdd, "+"},
@@ -418,7 +444,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1y - 2M",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "y"},
 				{tokenOperatorSub, "-"},
@@ -428,7 +454,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1y2M3w4d5h6m7s",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "y"},
 				{tokenOperatorAdd, ""},
@@ -453,7 +479,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1y 2M 3w 4d 5h 6m 7s",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "y"},
 				{tokenOperatorAdd, " "},
@@ -478,7 +504,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1y 2M and 3w & 4d, 5h + 6m - 7s",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "y"},
 				{tokenOperatorAdd, " "},
@@ -503,7 +529,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1y 2M and 3w&4d,5h+6m-7s ",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "y"},
 				{tokenOperatorAdd, " "},
@@ -528,7 +554,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1y ago",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "y"},
 				{tokenAgo, "ago"},
@@ -536,7 +562,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1y before now",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "y"},
 				{tokenBefore, "before"},
@@ -545,7 +571,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1y after now",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "y"},
 				{tokenFrom, "after"},
@@ -554,7 +580,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1y from now",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "y"},
 				{tokenFrom, "from"},
@@ -564,64 +590,64 @@ func TestLexer(t *testing.T) {
 		// rhs
 		{
 			"now",
-			[]token{
+			[]lexeme{
 				{tokenNow, "now"},
 			},
 		},
 		{
 			"today",
-			[]token{
+			[]lexeme{
 				{tokenDate, "today"},
 			},
 		},
 		{
 			"tomorrow",
-			[]token{
+			[]lexeme{
 				{tokenDate, "tomorrow"},
 			},
 		},
 		{
 			"yesterday",
-			[]token{
+			[]lexeme{
 				{tokenDate, "yesterday"},
 			},
 		},
 		{
 			"midnight",
-			[]token{
+			[]lexeme{
 				{tokenTime, "midnight"},
 			},
 		},
 		{
 			"noon",
-			[]token{
+			[]lexeme{
 				{tokenTime, "noon"},
 			},
 		},
 		{
 			"3am",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "3"},
 				{tokenTwelveHour, "am"},
 			},
 		},
 		{
 			"3pm",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "3"},
 				{tokenTwelveHour, "pm"},
 			},
 		},
 		{
 			"3 PM",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "3"},
 				{tokenTwelveHour, "PM"},
 			},
 		},
 		{
 			"3 in the afternoon",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "3"},
 				{tokenKeyword, "in"},
 				{tokenKeyword, "the"},
@@ -630,7 +656,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"3 oclock in the afternoon",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "3"},
 				{tokenKeyword, "oclock"},
 				{tokenKeyword, "in"},
@@ -640,7 +666,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"3 o'clock in the afternoon",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "3"},
 				{tokenKeyword, "o'clock"},
 				{tokenKeyword, "in"},
@@ -650,7 +676,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"3:04pm",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "3"},
 				{tokenColon, ":"},
 				{tokenDigit, "04"},
@@ -659,7 +685,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"3:04 PM",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "3"},
 				{tokenColon, ":"},
 				{tokenDigit, "04"},
@@ -668,7 +694,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"15:04",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "15"},
 				{tokenColon, ":"},
 				{tokenDigit, "04"},
@@ -676,7 +702,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"15:04:05",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "15"},
 				{tokenColon, ":"},
 				{tokenDigit, "04"},
@@ -686,7 +712,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"quarter to 4pm",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "quarter"},
 				{tokenKeyword, "to"},
 				{tokenDigit, "4"},
@@ -695,7 +721,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"quarter after 4pm",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "quarter"},
 				{tokenKeyword, "after"},
 				{tokenDigit, "4"},
@@ -704,7 +730,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"quarter past 4pm",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "quarter"},
 				{tokenKeyword, "past"},
 				{tokenDigit, "4"},
@@ -713,7 +739,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"half past 4pm",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "half"},
 				{tokenKeyword, "past"},
 				{tokenDigit, "4"},
@@ -722,7 +748,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"@3pm",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "@"},
 				{tokenDigit, "3"},
 				{tokenTwelveHour, "pm"},
@@ -730,7 +756,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"@ 3pm",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "@"},
 				{tokenDigit, "3"},
 				{tokenTwelveHour, "pm"},
@@ -738,7 +764,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"at 3pm",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "at"},
 				{tokenDigit, "3"},
 				{tokenTwelveHour, "pm"},
@@ -746,20 +772,20 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"at noon",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "at"},
 				{tokenTime, "noon"},
 			},
 		},
 		{
 			"2006",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "2006"},
 			},
 		},
 		{
 			"2006-01",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "2006"},
 				{tokenDateSeparator, "-"},
 				{tokenDigit, "01"},
@@ -767,7 +793,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"2006-01-02",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "2006"},
 				{tokenDateSeparator, "-"},
 				{tokenDigit, "01"},
@@ -777,7 +803,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"2006/01",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "2006"},
 				{tokenDateSeparator, "/"},
 				{tokenDigit, "01"},
@@ -785,7 +811,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"2006/01/02",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "2006"},
 				{tokenDateSeparator, "/"},
 				{tokenDigit, "01"},
@@ -793,63 +819,97 @@ func TestLexer(t *testing.T) {
 				{tokenDigit, "02"},
 			},
 		},
+		{
+			"02.01.2006",
+			[]lexeme{
+				{tokenDigit, "02"},
+				{tokenDateSeparator, "."},
+				{tokenDigit, "01"},
+				{tokenDateSeparator, "."},
+				{tokenDigit, "2006"},
+			},
+		},
+		{
+			"1.5 days",
+			[]lexeme{
+				{tokenDigit, "1.5"},
+				{tokenUnit, "days"},
+			},
+		},
+		{
+			"3/4 hour",
+			[]lexeme{
+				{tokenDigit, "3/4"},
+				{tokenUnit, "hour"},
+			},
+		},
+		{
+			"3/4 at noon",
+			[]lexeme{
+				{tokenDigit, "3"},
+				{tokenDateSeparator, "/"},
+				{tokenDigit, "4"},
+				{tokenKeyword, "at"},
+				{tokenTime, "noon"},
+			},
+		},
 		{
 			"Sunday",
-			[]token{
+			[]lexeme{
 				{tokenWeekday, time.Sunday.String()},
 			},
 		},
 		{
 			"on Wednesday",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "on"},
 				{tokenWeekday, time.Wednesday.String()},
 			},
 		},
 		{
 			"January",
-			[]token{
+			[]lexeme{
 				{tokenMonth, time.January.String()},
 			},
 		},
 		{
 			"on November",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "on"},
 				{tokenMonth, time.November.String()},
 			},
 		},
 		{
 			"1st",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenOrdinal, "st"},
 			},
 		},
 		{
 			"2nd",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "2"},
 				{tokenOrdinal, "nd"},
 			},
 		},
 		{
 			"3rd",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "3"},
 				{tokenOrdinal, "rd"},
 			},
 		},
 		{
 			"4th",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "4"},
 				{tokenOrdinal, "th"},
 			},
 		},
 		{
 			"4th of the month",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "4"},
 				{tokenOrdinal, "th"},
 				{tokenKeyword, "of"},
@@ -859,7 +919,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"4th of last month",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "4"},
 				{tokenOrdinal, "th"},
 				{tokenKeyword, "of"},
@@ -869,7 +929,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"4th of next month",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "4"},
 				{tokenOrdinal, "th"},
 				{tokenKeyword, "of"},
@@ -879,7 +939,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"last day of March",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "last"},
 				{tokenUnit, "day"},
 				{tokenKeyword, "of"},
@@ -888,7 +948,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"last day of the month",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "last"},
 				{tokenUnit, "day"},
 				{tokenKeyword, "of"},
@@ -898,7 +958,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"last day of last month",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "last"},
 				{tokenUnit, "day"},
 				{tokenKeyword, "of"},
@@ -908,7 +968,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"last day of next month",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "last"},
 				{tokenUnit, "day"},
 				{tokenKeyword, "of"},
@@ -918,7 +978,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"2nd last day of March",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "2"},
 				{tokenOrdinal, "nd"},
 				{tokenKeyword, "last"},
@@ -929,7 +989,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"2nd last day of the month",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "2"},
 				{tokenOrdinal, "nd"},
 				{tokenKeyword, "last"},
@@ -941,7 +1001,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"2nd last day of last month",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "2"},
 				{tokenOrdinal, "nd"},
 				{tokenKeyword, "last"},
@@ -953,7 +1013,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"2nd last day of next month",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "2"},
 				{tokenOrdinal, "nd"},
 				{tokenKeyword, "last"},
@@ -965,7 +1025,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"2nd Tuesday of March",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "2"},
 				{tokenOrdinal, "nd"},
 				{tokenWeekday, time.Tuesday.String()},
@@ -975,7 +1035,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"2nd Tuesday of the month",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "2"},
 				{tokenOrdinal, "nd"},
 				{tokenWeekday, time.Tuesday.String()},
@@ -986,7 +1046,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"2nd Tuesday of last month",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "2"},
 				{tokenOrdinal, "nd"},
 				{tokenWeekday, time.Tuesday.String()},
@@ -997,7 +1057,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"2nd Tuesday of next month",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "2"},
 				{tokenOrdinal, "nd"},
 				{tokenWeekday, time.Tuesday.String()},
@@ -1008,7 +1068,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"last Tuesday of March",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "last"},
 				{tokenWeekday, time.Tuesday.String()},
 				{tokenKeyword, "of"},
@@ -1017,7 +1077,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"last Tuesday of the month",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "last"},
 				{tokenWeekday, time.Tuesday.String()},
 				{tokenKeyword, "of"},
@@ -1027,7 +1087,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"last Tuesday of last month",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "last"},
 				{tokenWeekday, time.Tuesday.String()},
 				{tokenKeyword, "of"},
@@ -1037,7 +1097,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"last Tuesday of next month",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "last"},
 				{tokenWeekday, time.Tuesday.String()},
 				{tokenKeyword, "of"},
@@ -1047,7 +1107,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"2nd last Tuesday of March",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "2"},
 				{tokenOrdinal, "nd"},
 				{tokenKeyword, "last"},
@@ -1058,7 +1118,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"2nd last Tuesday of the month",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "2"},
 				{tokenOrdinal, "nd"},
 				{tokenKeyword, "last"},
@@ -1070,7 +1130,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"2nd last Tuesday of last month",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "2"},
 				{tokenOrdinal, "nd"},
 				{tokenKeyword, "last"},
@@ -1082,7 +1142,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"2nd last Tuesday of next month",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "2"},
 				{tokenOrdinal, "nd"},
 				{tokenKeyword, "last"},
@@ -1094,7 +1154,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"on the 4th",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "on"},
 				{tokenKeyword, "the"},
 				{tokenDigit, "4"},
@@ -1103,7 +1163,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"on the 4th at 4pm",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "on"},
 				{tokenKeyword, "the"},
 				{tokenDigit, "4"},
@@ -1115,7 +1175,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"at 4pm on the 4th",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "at"},
 				{tokenDigit, "4"},
 				{tokenTwelveHour, "pm"},
@@ -1127,7 +1187,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"on Wednesday at 4pm",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "on"},
 				{tokenWeekday, time.Wednesday.String()},
 				{tokenKeyword, "at"},
@@ -1137,7 +1197,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"at 4pm on Wednesday",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "at"},
 				{tokenDigit, "4"},
 				{tokenTwelveHour, "pm"},
@@ -1147,7 +1207,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"on March 14th at noon",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "on"},
 				{tokenMonth, time.March.String()},
 				{tokenDigit, "14"},
@@ -1158,7 +1218,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"on March the 14th at noon",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "on"},
 				{tokenMonth, time.March.String()},
 				{tokenKeyword, "the"},
@@ -1170,7 +1230,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"on the 14th March at noon",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "on"},
 				{tokenKeyword, "the"},
 				{tokenDigit, "14"},
@@ -1182,7 +1242,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"on the 14th of March at noon",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "on"},
 				{tokenKeyword, "the"},
 				{tokenDigit, "14"},
@@ -1195,7 +1255,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"at noon on the 14th of March",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "at"},
 				{tokenTime, "noon"},
 				{tokenKeyword, "on"},
@@ -1208,7 +1268,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"on the 2nd Tuesday of March at noon",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "on"},
 				{tokenKeyword, "the"},
 				{tokenDigit, "2"},
@@ -1222,7 +1282,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"on the 2nd Tuesday in March at noon",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "on"},
 				{tokenKeyword, "the"},
 				{tokenDigit, "2"},
@@ -1236,7 +1296,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"at noon on the 2nd Tuesday of March",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "at"},
 				{tokenTime, "noon"},
 				{tokenKeyword, "on"},
@@ -1250,7 +1310,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"at noon on the 2nd Tuesday in March",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "at"},
 				{tokenTime, "noon"},
 				{tokenKeyword, "on"},
@@ -1264,7 +1324,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"on the 2nd last Tuesday of March at noon",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "on"},
 				{tokenKeyword, "the"},
 				{tokenDigit, "2"},
@@ -1279,7 +1339,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"on the 2nd last Tuesday in March at noon",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "on"},
 				{tokenKeyword, "the"},
 				{tokenDigit, "2"},
@@ -1294,7 +1354,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"at noon on the 2nd last Tuesday of March",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "at"},
 				{tokenTime, "noon"},
 				{tokenKeyword, "on"},
@@ -1309,7 +1369,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"at noon on the 2nd last Tuesday in March",
-			[]token{
+			[]lexeme{
 				{tokenKeyword, "at"},
 				{tokenTime, "noon"},
 				{tokenKeyword, "on"},
@@ -1325,7 +1385,7 @@ func TestLexer(t *testing.T) {
 		// rhs arithmetic
 		{
 			"now + 2 days",
-			[]token{
+			[]lexeme{
 				{tokenNow, "now"},
 				{tokenOperatorAdd, "+"},
 				{tokenDigit, "2"},
@@ -1334,7 +1394,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"now - 2 days",
-			[]token{
+			[]lexeme{
 				{tokenNow, "now"},
 				{tokenOperatorSub, "-"},
 				{tokenDigit, "2"},
@@ -1344,7 +1404,7 @@ func TestLexer(t *testing.T) {
 		// lhs/rhs
 		{
 			"7 weeks from Jan 5th at 4pm + 5 days",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "7"},
 				{tokenUnit, "weeks"},
 				{tokenFrom, "from"},
@@ -1361,7 +1421,7 @@ func TestLexer(t *testing.T) {
 		},
 		{
 			"1y 2M and 3w & 4d, 5h from quarter past 3 o'clock in the afternoon on the 2nd Tuesday of March + 6 minutes - 7 seconds",
-			[]token{
+			[]lexeme{
 				{tokenDigit, "1"},
 				{tokenUnit, "y"},
 				{tokenOperatorAdd, " "},
@@ -1399,12 +1459,302 @@ func TestLexer(t *testing.T) {
 				{tokenUnit, "seconds"},
 			},
 		},
+		// zones
+		{
+			"3pm PST",
+			[]lexeme{
+				{tokenDigit, "3"},
+				{tokenTwelveHour, "pm"},
+				{tokenZone, "PST"},
+			},
+		},
+		{
+			"noon Europe/London",
+			[]lexeme{
+				{tokenTime, "noon"},
+				{tokenZone, "Europe/London"},
+			},
+		},
+		{
+			"9am +05:30",
+			[]lexeme{
+				{tokenDigit, "9"},
+				{tokenTwelveHour, "am"},
+				{tokenZone, "+05:30"},
+			},
+		},
+		{
+			"14:00 -0800 + 1h",
+			[]lexeme{
+				{tokenDigit, "14"},
+				{tokenColon, ":"},
+				{tokenDigit, "00"},
+				{tokenZone, "-0800"},
+				{tokenOperatorAdd, "+"},
+				{tokenDigit, "1"},
+				{tokenUnit, "h"},
+			},
+		},
+		{
+			"9:00-10:00",
+			[]lexeme{
+				{tokenDigit, "9"},
+				{tokenColon, ":"},
+				{tokenDigit, "00"},
+				{tokenDateSeparator, "-"},
+				{tokenDigit, "10"},
+				{tokenColon, ":"},
+				{tokenDigit, "00"},
+			},
+		},
+		// timestamps
+		{
+			"2024-03-01T10:00:00Z + 2h",
+			[]lexeme{
+				{tokenTimestamp, "2024-03-01T10:00:00Z"},
+				{tokenOperatorAdd, "+"},
+				{tokenDigit, "2"},
+				{tokenUnit, "h"},
+			},
+		},
+		{
+			"on 2006-W01-2 at 3pm",
+			[]lexeme{
+				{tokenKeyword, "on"},
+				{tokenTimestamp, "2006-W01-2"},
+				{tokenKeyword, "at"},
+				{tokenDigit, "3"},
+				{tokenTwelveHour, "pm"},
+			},
+		},
+		{
+			"2006-002",
+			[]lexeme{
+				{tokenTimestamp, "2006-002"},
+			},
+		},
+		{
+			"20060102T150405.5 -07:00",
+			[]lexeme{
+				{tokenTimestamp, "20060102T150405.5"},
+				{tokenZone, "-07:00"},
+			},
+		},
+		{
+			"2006-01-02",
+			[]lexeme{
+				{tokenDigit, "2006"},
+				{tokenDateSeparator, "-"},
+				{tokenDigit, "01"},
+				{tokenDateSeparator, "-"},
+				{tokenDigit, "02"},
+			},
+		},
+		// sub-second units
+		{
+			"500ms 250 µs",
+			[]lexeme{
+				{tokenDigit, "500"},
+				{tokenUnit, "ms"},
+				{tokenOperatorAdd, " "},
+				{tokenDigit, "250"},
+				{tokenUnit, "µs"},
+			},
+		},
+		{
+			"15:04:05.250",
+			[]lexeme{
+				{tokenDigit, "15"},
+				{tokenColon, ":"},
+				{tokenDigit, "04"},
+				{tokenColon, ":"},
+				{tokenDigit, "05.250"},
+			},
+		},
+		// quantities
+		{
+			"2.5d",
+			[]lexeme{
+				{tokenDigit, "2.5"},
+				{tokenUnit, "d"},
+			},
+		},
+		{
+			"half an hour",
+			[]lexeme{
+				{tokenKeyword, "half"},
+				{tokenDigit, "1"},
+				{tokenUnit, "hour"},
+			},
+		},
+		// number words
+		{
+			"twenty-third of March",
+			[]lexeme{
+				{tokenDigit, "23"},
+				{tokenOrdinal, "rd"},
+				{tokenKeyword, "of"},
+				{tokenMonth, "March"},
+			},
+		},
+		{
+			"1 day a couple of hours",
+			[]lexeme{
+				{tokenDigit, "1"},
+				{tokenUnit, "day"},
+				{tokenOperatorAdd, " "},
+				{tokenDigit, "2"},
+				{tokenUnit, "hours"},
+			},
+		},
+		// durations
+		{
+			"PT90M ago",
+			[]lexeme{
+				{tokenDuration, "PT90M"},
+				{tokenAgo, "ago"},
+			},
+		},
+		{
+			"now + P2W",
+			[]lexeme{
+				{tokenNow, "now"},
+				{tokenOperatorAdd, "+"},
+				{tokenDuration, "P2W"},
+			},
+		},
+		// working time
+		{
+			"3 Business  Days from now",
+			[]lexeme{
+				{tokenDigit, "3"},
+				{tokenUnit, "business days"},
+				{tokenFrom, "from"},
+				{tokenNow, "now"},
+			},
+		},
+		{
+			"4 working hours after fri 4pm",
+			[]lexeme{
+				{tokenDigit, "4"},
+				{tokenUnit, "working hours"},
+				{tokenFrom, "after"},
+				{tokenWeekday, "fri"},
+				{tokenDigit, "4"},
+				{tokenTwelveHour, "pm"},
+			},
+		},
+		{
+			"2 workdays ago",
+			[]lexeme{
+				{tokenDigit, "2"},
+				{tokenUnit, "workdays"},
+				{tokenAgo, "ago"},
+			},
+		},
+		// holidays
+		{
+			"christmas eve 2025",
+			[]lexeme{
+				{tokenHoliday, "Christmas Eve"},
+				{tokenDigit, "2025"},
+			},
+		},
+		{
+			"the day after  labor-day",
+			[]lexeme{
+				{tokenKeyword, "the"},
+				{tokenUnit, "day"},
+				{tokenFrom, "after"},
+				{tokenHoliday, "Labor Day"},
+			},
+		},
+		{
+			"new years day at 3pm",
+			[]lexeme{
+				{tokenHoliday, "New Year's Day"},
+				{tokenKeyword, "at"},
+				{tokenDigit, "3"},
+				{tokenTwelveHour, "pm"},
+			},
+		},
+		// periods
+		{
+			"q3 2025",
+			[]lexeme{
+				{tokenPeriod, "Q3"},
+				{tokenDigit, "2025"},
+			},
+		},
+		{
+			"H1 fy26",
+			[]lexeme{
+				{tokenPeriod, "H1"},
+				{tokenPeriod, "FY2026"},
+			},
+		},
+		{
+			"next quarter",
+			[]lexeme{
+				{tokenKeyword, "next"},
+				{tokenKeyword, "quarter"},
+			},
+		},
+		// relative periods
+		{
+			"this Friday",
+			[]lexeme{
+				{tokenKeyword, "this"},
+				{tokenWeekday, "Friday"},
+			},
+		},
+		{
+			"the week after next",
+			[]lexeme{
+				{tokenKeyword, "the"},
+				{tokenUnit, "week"},
+				{tokenFrom, "after"},
+				{tokenKeyword, "next"},
+			},
+		},
+		{
+			"Previous month",
+			[]lexeme{
+				{tokenKeyword, "previous"},
+				{tokenUnit, "month"},
+			},
+		},
+		// boundaries
+		{
+			"Start of next week",
+			[]lexeme{
+				{tokenKeyword, "start"},
+				{tokenKeyword, "of"},
+				{tokenKeyword, "next"},
+				{tokenUnit, "week"},
+			},
+		},
+		{
+			"EOD",
+			[]lexeme{
+				{tokenKeyword, "eod"},
+			},
+		},
+		{
+			"end of Q2",
+			[]lexeme{
+				{tokenKeyword, "end"},
+				{tokenKeyword, "of"},
+				{tokenPeriod, "Q2"},
+			},
+		},
 	}
 	for _, tt := range tests {
-		have, err := lex(tt.in)
+		tokens, err := lex(tt.in)
 		if err != nil {
 			t.Fatalf("lex(%q) %v", tt.in, err)
 		}
+		have := lexemes(tokens)
 		if !reflect.DeepEqual(have, tt.want) {
 			t.Errorf("lex(%q)\nhave %#v\nwant %#v", tt.in, have, tt.want)
 		}
@@ -1424,6 +1774,15 @@ func TestLexerError(t *testing.T) {
 		"one year2 months",
 		"1 year2M",
 		"one year2M",
+		"3pm Nowhere/Special",
+		"3pm XYZ",
+		"2 working",
+		"2 business weeks",
+		"christmastide",
+		"Q5",
+		"H3",
+		"FY202",
+		"Q3x",
 	}
 	for _, tc := range tests {
 		have, err := lex(tc)
@@ -1432,3 +1791,66 @@ func TestLexerError(t *testing.T) {
 		}
 	}
 }
+
+func TestLexerPosition(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []token
+	}{
+		{
+			"six hours ago",
+			[]token{
+				{tokenDigit, "6", 0, 3},
+				{tokenUnit, "hours", 4, 9},
+				{tokenAgo, "ago", 10, 13},
+			},
+		},
+		{
+			"1 year two months",
+			[]token{
+				{tokenDigit, "1", 0, 1},
+				{tokenUnit, "year", 2, 6},
+				{tokenOperatorAdd, " ", 6, 7},
+				{tokenDigit, "2", 7, 10},
+				{tokenUnit, "months", 11, 17},
+			},
+		},
+		{
+			"the first",
+			[]token{
+				{tokenKeyword, "the", 0, 3},
+				{tokenDigit, "1", 4, 9},
+				{tokenOrdinal, "st", 4, 9},
+			},
+		},
+		{
+			"thirty-first of March",
+			[]token{
+				{tokenDigit, "31", 0, 12},
+				{tokenOrdinal, "st", 7, 12},
+				{tokenKeyword, "of", 13, 15},
+				{tokenMonth, "March", 16, 21},
+			},
+		},
+		{
+			"  Jan 2nd @3pm",
+			[]token{
+				{tokenMonth, "Jan", 2, 5},
+				{tokenDigit, "2", 6, 7},
+				{tokenOrdinal, "nd", 7, 9},
+				{tokenKeyword, "@", 10, 11},
+				{tokenDigit, "3", 11, 12},
+				{tokenTwelveHour, "pm", 12, 14},
+			},
+		},
+	}
+	for _, tt := range tests {
+		have, err := lex(tt.in)
+		if err != nil {
+			t.Fatalf("lex(%q) %v", tt.in, err)
+		}
+		if !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("lex(%q)\nhave %v\nwant %v", tt.in, have, tt.want)
+		}
+	}
+}
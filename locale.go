@@ -0,0 +1,334 @@
+package when
+
+import (
+	"strings"
+	"time"
+)
+
+// Locale is the vocabulary and word order of a language. Words are
+// translated to the English vocabulary of the parser, which remains
+// understood alongside them, so numeric dates, times, zones and short
+// units such as "2h" are written the same in every language. Words are
+// matched without regard to case and are given in lower case.
+type Locale struct {
+	// Units maps unit words to English units, as in "tagen" to "days".
+	Units map[string]string
+	// Months maps the names of months to months.
+	Months map[string]time.Month
+	// Weekdays maps the names of weekdays to weekdays.
+	Weekdays map[string]time.Weekday
+	// Numbers maps spelled-out numbers to their value. Numbers are read
+	// as whole words and are not combined, so compounds such as
+	// "einundzwanzig" are read only if they are listed.
+	Numbers map[string]int
+	// Ordinals are the suffixes written after a number to make it an
+	// ordinal, as in "3." or "3º".
+	Ordinals []string
+	// Keywords maps other words to the English word of the same meaning,
+	// as in "morgen" to "tomorrow" and "nächsten" to "next". Words mapped
+	// to the empty string, such as articles, are skipped.
+	Keywords map[string]string
+	// Order is the word order of the language.
+	Order WordOrder
+}
+
+// WordOrder describes where the word order of a language differs from
+// English.
+type WordOrder struct {
+	// AgoFirst places the word for "ago" before the duration, as in
+	// "vor 2 Tagen".
+	AgoFirst bool
+	// QualifierAfter places words such as "next" and "last" after the
+	// month, weekday or unit they qualify, as in "el martes pasado".
+	QualifierAfter bool
+	// DayFirst allows the day of the month before the name of the month
+	// without an ordinal suffix, as in "3 de marzo".
+	DayFirst bool
+}
+
+// ordinal returns the length of the ordinal suffix at the start of s, or
+// zero if there is none.
+func (l *Locale) ordinal(s string) int {
+	for _, o := range l.Ordinals {
+		if strings.HasPrefix(s, o) {
+			return len(o)
+		}
+	}
+	return 0
+}
+
+// German is the vocabulary of the German language.
+var German = &Locale{
+	Units: map[string]string{
+		"jahr":     "year",
+		"jahre":    "years",
+		"jahren":   "years",
+		"jahres":   "year",
+		"monat":    "month",
+		"monate":   "months",
+		"monaten":  "months",
+		"monats":   "month",
+		"woche":    "week",
+		"wochen":   "weeks",
+		"tag":      "day",
+		"tage":     "days",
+		"tagen":    "days",
+		"tages":    "day",
+		"stunde":   "hour",
+		"stunden":  "hours",
+		"minute":   "minute",
+		"minuten":  "minutes",
+		"sekunde":  "second",
+		"sekunden": "seconds",
+	},
+	Months: map[string]time.Month{
+		"januar":    time.January,
+		"jan":       time.January,
+		"februar":   time.February,
+		"feb":       time.February,
+		"märz":      time.March,
+		"mär":       time.March,
+		"april":     time.April,
+		"apr":       time.April,
+		"mai":       time.May,
+		"juni":      time.June,
+		"jun":       time.June,
+		"juli":      time.July,
+		"jul":       time.July,
+		"august":    time.August,
+		"aug":       time.August,
+		"september": time.September,
+		"sep":       time.September,
+		"oktober":   time.October,
+		"okt":       time.October,
+		"november":  time.November,
+		"nov":       time.November,
+		"dezember":  time.December,
+		"dez":       time.December,
+	},
+	Weekdays: map[string]time.Weekday{
+		"sonntag":    time.Sunday,
+		"montag":     time.Monday,
+		"dienstag":   time.Tuesday,
+		"mittwoch":   time.Wednesday,
+		"donnerstag": time.Thursday,
+		"freitag":    time.Friday,
+		"samstag":    time.Saturday,
+		"sonnabend":  time.Saturday,
+	},
+	Numbers: map[string]int{
+		"ein":      1,
+		"eine":     1,
+		"einem":    1,
+		"einer":    1,
+		"eins":     1,
+		"zwei":     2,
+		"drei":     3,
+		"vier":     4,
+		"fünf":     5,
+		"sechs":    6,
+		"sieben":   7,
+		"acht":     8,
+		"neun":     9,
+		"zehn":     10,
+		"elf":      11,
+		"zwölf":    12,
+		"dreizehn": 13,
+		"vierzehn": 14,
+		"fünfzehn": 15,
+		"sechzehn": 16,
+		"siebzehn": 17,
+		"achtzehn": 18,
+		"neunzehn": 19,
+		"zwanzig":  20,
+		"dreißig":  30,
+		"vierzig":  40,
+		"fünfzig":  50,
+		"sechzig":  60,
+	},
+	Ordinals: []string{"."},
+	Keywords: map[string]string{
+		"jetzt":       "now",
+		"heute":       "today",
+		"morgen":      "tomorrow",
+		"gestern":     "yesterday",
+		"mittag":      "noon",
+		"mitternacht": "midnight",
+		"vor":         "ago",
+		"von":         "from",
+		"bis":         "until",
+		"zwischen":    "between",
+		"nach":        "after",
+		"um":          "at",
+		"und":         "and",
+		"des":         "of",
+		"der":         "of",
+		"diese":       "this",
+		"diesen":      "this",
+		"dieser":      "this",
+		"dieses":      "this",
+		"nächste":     "next",
+		"nächsten":    "next",
+		"nächster":    "next",
+		"nächstes":    "next",
+		"kommende":    "coming",
+		"kommenden":   "coming",
+		"letzte":      "last",
+		"letzten":     "last",
+		"letzter":     "last",
+		"letztes":     "last",
+		"vorige":      "previous",
+		"vorigen":     "previous",
+		"anfang":      "start",
+		"ende":        "end",
+		"am":          "",
+		"im":          "",
+	},
+	Order: WordOrder{AgoFirst: true, DayFirst: true},
+}
+
+// Spanish is the vocabulary of the Spanish language.
+var Spanish = &Locale{
+	Units: map[string]string{
+		"año":      "year",
+		"años":     "years",
+		"mes":      "month",
+		"meses":    "months",
+		"semana":   "week",
+		"semanas":  "weeks",
+		"día":      "day",
+		"días":     "days",
+		"dia":      "day",
+		"dias":     "days",
+		"hora":     "hour",
+		"horas":    "hours",
+		"minuto":   "minute",
+		"minutos":  "minutes",
+		"segundo":  "second",
+		"segundos": "seconds",
+	},
+	Months: map[string]time.Month{
+		"enero":      time.January,
+		"ene":        time.January,
+		"febrero":    time.February,
+		"feb":        time.February,
+		"marzo":      time.March,
+		"mar":        time.March,
+		"abril":      time.April,
+		"abr":        time.April,
+		"mayo":       time.May,
+		"junio":      time.June,
+		"jun":        time.June,
+		"julio":      time.July,
+		"jul":        time.July,
+		"agosto":     time.August,
+		"septiembre": time.September,
+		"setiembre":  time.September,
+		"sep":        time.September,
+		"octubre":    time.October,
+		"oct":        time.October,
+		"noviembre":  time.November,
+		"nov":        time.November,
+		"diciembre":  time.December,
+		"dic":        time.December,
+	},
+	Weekdays: map[string]time.Weekday{
+		"domingo":   time.Sunday,
+		"lunes":     time.Monday,
+		"martes":    time.Tuesday,
+		"miércoles": time.Wednesday,
+		"miercoles": time.Wednesday,
+		"jueves":    time.Thursday,
+		"viernes":   time.Friday,
+		"sábado":    time.Saturday,
+		"sabado":    time.Saturday,
+	},
+	Numbers: map[string]int{
+		"un":           1,
+		"uno":          1,
+		"una":          1,
+		"dos":          2,
+		"tres":         3,
+		"cuatro":       4,
+		"cinco":        5,
+		"seis":         6,
+		"siete":        7,
+		"ocho":         8,
+		"nueve":        9,
+		"diez":         10,
+		"once":         11,
+		"doce":         12,
+		"trece":        13,
+		"catorce":      14,
+		"quince":       15,
+		"dieciséis":    16,
+		"dieciseis":    16,
+		"diecisiete":   17,
+		"dieciocho":    18,
+		"diecinueve":   19,
+		"veinte":       20,
+		"veintiuno":    21,
+		"veintiún":     21,
+		"veintiun":     21,
+		"veintidós":    22,
+		"veintidos":    22,
+		"veintitrés":   23,
+		"veintitres":   23,
+		"veinticuatro": 24,
+		"veinticinco":  25,
+		"veintiséis":   26,
+		"veintiseis":   26,
+		"veintisiete":  27,
+		"veintiocho":   28,
+		"veintinueve":  29,
+		"treinta":      30,
+		"cuarenta":     40,
+		"cincuenta":    50,
+		"sesenta":      60,
+	},
+	Ordinals: []string{"º", "°"},
+	Keywords: map[string]string{
+		"ahora":      "now",
+		"hoy":        "today",
+		"mañana":     "tomorrow",
+		"manana":     "tomorrow",
+		"ayer":       "yesterday",
+		"mediodía":   "noon",
+		"mediodia":   "noon",
+		"medianoche": "midnight",
+		"hace":       "ago",
+		"después":    "after",
+		"despues":    "after",
+		"desde":      "from",
+		"hasta":      "until",
+		"entre":      "between",
+		"a":          "at",
+		"en":         "in",
+		"y":          "and",
+		"de":         "of",
+		"del":        "of",
+		"este":       "this",
+		"esta":       "this",
+		"próximo":    "next",
+		"próxima":    "next",
+		"proximo":    "next",
+		"proxima":    "next",
+		"pasado":     "last",
+		"pasada":     "last",
+		"último":     "last",
+		"última":     "last",
+		"ultimo":     "last",
+		"ultima":     "last",
+		"anterior":   "previous",
+		"pasados":    "past",
+		"pasadas":    "past",
+		"principio":  "start",
+		"inicio":     "start",
+		"fin":        "end",
+		"el":         "",
+		"la":         "",
+		"las":        "",
+		"los":        "",
+	},
+	Order: WordOrder{AgoFirst: true, QualifierAfter: true, DayFirst: true},
+}
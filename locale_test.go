@@ -0,0 +1,144 @@
+package when
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseLocale(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	tests := []struct {
+		locale *Locale
+		in     string
+		want   time.Time
+	}{
+		{German, "jetzt", now},
+		{German, "vor 2 Tagen", time.Date(2005, time.December, 31, 15, 4, 5, 0, loc)},
+		{German, "in drei Stunden", time.Date(2006, time.January, 2, 18, 4, 5, 0, loc)},
+		{German, "in einer Woche", time.Date(2006, time.January, 9, 15, 4, 5, 0, loc)},
+		{German, "2 Tage und 3 Stunden", time.Date(2006, time.January, 4, 18, 4, 5, 0, loc)},
+		{German, "Morgen um 15:00", time.Date(2006, time.January, 3, 15, 0, 0, 0, loc)},
+		{German, "nächsten Freitag", time.Date(2006, time.January, 13, 0, 0, 0, 0, loc)},
+		{German, "letzten Montag", time.Date(2005, time.December, 26, 0, 0, 0, 0, loc)},
+		{German, "am 3. März um 15:00", time.Date(2006, time.March, 3, 15, 0, 0, 0, loc)},
+		{German, "3 MÄRZ", time.Date(2006, time.March, 3, 0, 0, 0, 0, loc)},
+		{German, "diese Woche", time.Date(2006, time.January, 1, 0, 0, 0, 0, loc)},
+		{German, "Ende des Monats", time.Date(2006, time.February, 1, 0, 0, 0, 0, loc).Add(-1)},
+		{German, "tomorrow at 3pm", time.Date(2006, time.January, 3, 15, 0, 0, 0, loc)},
+		{German, "tomorrow at 9 am", time.Date(2006, time.January, 3, 9, 0, 0, 0, loc)},
+		{German, "am Montag um 9 am", time.Date(2006, time.January, 9, 9, 0, 0, 0, loc)},
+		{German, "vor zwanzig Minuten", time.Date(2006, time.January, 2, 14, 44, 5, 0, loc)},
+		{Spanish, "ahora", now},
+		{Spanish, "a day ago", time.Date(2006, time.January, 1, 15, 4, 5, 0, loc)},
+		{Spanish, "en a week", time.Date(2006, time.January, 9, 15, 4, 5, 0, loc)},
+		{Spanish, "hace veinticinco minutos", time.Date(2006, time.January, 2, 14, 39, 5, 0, loc)},
+		{Spanish, "hace 2 días", time.Date(2005, time.December, 31, 15, 4, 5, 0, loc)},
+		{Spanish, "en dos semanas", time.Date(2006, time.January, 16, 15, 4, 5, 0, loc)},
+		{Spanish, "mañana a las 15:00", time.Date(2006, time.January, 3, 15, 0, 0, 0, loc)},
+		{Spanish, "el próximo viernes", time.Date(2006, time.January, 13, 0, 0, 0, 0, loc)},
+		{Spanish, "el martes pasado", time.Date(2005, time.December, 27, 0, 0, 0, 0, loc)},
+		{Spanish, "la semana pasada", time.Date(2005, time.December, 25, 0, 0, 0, 0, loc)},
+		{Spanish, "el mes próximo", time.Date(2006, time.February, 1, 0, 0, 0, 0, loc)},
+		{Spanish, "el 3 de marzo a las 3pm", time.Date(2006, time.March, 3, 15, 0, 0, 0, loc)},
+		{Spanish, "1º de mayo", time.Date(2006, time.May, 1, 0, 0, 0, 0, loc)},
+		{Spanish, "a las 3pm el martes pasado", time.Date(2005, time.December, 27, 15, 0, 0, 0, loc)},
+		{Spanish, "fin de mes", time.Date(2006, time.February, 1, 0, 0, 0, 0, loc).Add(-1)},
+	}
+	for _, tt := range tests {
+		p := New(WithLocale(tt.locale))
+		have, err := p.ParseNow(tt.in, now)
+		if err != nil {
+			t.Errorf("ParseNow(%q) %v", tt.in, err)
+			continue
+		}
+		if !have.Equal(tt.want) {
+			t.Errorf("ParseNow(%q)\nhave %v\nwant %v", tt.in, have, tt.want)
+		}
+	}
+}
+
+func TestParseLocaleError(t *testing.T) {
+	tests := []struct {
+		locale *Locale
+		in     string
+	}{
+		{nil, "vor 2 Tagen"},
+		{nil, "ago 2 days"},
+		{German, "vor"},
+		{German, "vor 2 Tagen um 3pm"},
+		{German, "3. 4."},
+		{Spanish, "hace mañana"},
+		{German, "nächsten"},
+	}
+	for _, tt := range tests {
+		p := New(WithLocale(tt.locale))
+		have, err := p.ParseExpr(tt.in)
+		if err == nil {
+			t.Errorf("ParseExpr(%q)\nhave %v\nwant parse error", tt.in, have)
+		}
+	}
+}
+
+func TestLexerLocale(t *testing.T) {
+	tests := []struct {
+		locale *Locale
+		in     string
+		want   []lexeme
+	}{
+		{
+			German,
+			"vor zwei Tagen",
+			[]lexeme{
+				{tokenAgo, "ago"},
+				{tokenDigit, "2"},
+				{tokenUnit, "days"},
+			},
+		},
+		{
+			German,
+			"am 3. März",
+			[]lexeme{
+				{tokenDigit, "3"},
+				{tokenOrdinal, "rd"},
+				{tokenMonth, "March"},
+			},
+		},
+		{
+			Spanish,
+			"el viernes a las 3pm",
+			[]lexeme{
+				{tokenWeekday, "Friday"},
+				{tokenKeyword, "at"},
+				{tokenDigit, "3"},
+				{tokenTwelveHour, "pm"},
+			},
+		},
+		{
+			Spanish,
+			"2 días y 3 horas",
+			[]lexeme{
+				{tokenDigit, "2"},
+				{tokenUnit, "days"},
+				{tokenOperatorAdd, "and"},
+				{tokenDigit, "3"},
+				{tokenUnit, "hours"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		p := New(WithLocale(tt.locale))
+		tokens, err := p.lex(tt.in)
+		if err != nil {
+			t.Fatalf("lex(%q) %v", tt.in, err)
+		}
+		have := lexemes(tokens)
+		if !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("lex(%q)\nhave %#v\nwant %#v", tt.in, have, tt.want)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package when
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+var numberWords = map[string]int{
+	"zero":      0,
+	"one":       1,
+	"two":       2,
+	"three":     3,
+	"four":      4,
+	"five":      5,
+	"six":       6,
+	"seven":     7,
+	"eight":     8,
+	"nine":      9,
+	"ten":       10,
+	"eleven":    11,
+	"twelve":    12,
+	"thirteen":  13,
+	"fourteen":  14,
+	"fifteen":   15,
+	"sixteen":   16,
+	"seventeen": 17,
+	"eighteen":  18,
+	"nineteen":  19,
+}
+
+var tensWords = map[string]int{
+	"twenty":  20,
+	"thirty":  30,
+	"forty":   40,
+	"fifty":   50,
+	"sixty":   60,
+	"seventy": 70,
+	"eighty":  80,
+	"ninety":  90,
+}
+
+// ordinalWords excludes "second", which is read as a unit unless it is
+// hyphenated to a multiple of ten, as in "twenty-second", or followed by
+// a word that only an ordinal is, as in "second Tuesday" and "second of".
+var ordinalWords = map[string]int{
+	"first":       1,
+	"third":       3,
+	"fourth":      4,
+	"fifth":       5,
+	"sixth":       6,
+	"seventh":     7,
+	"eighth":      8,
+	"ninth":       9,
+	"tenth":       10,
+	"eleventh":    11,
+	"twelfth":     12,
+	"thirteenth":  13,
+	"fourteenth":  14,
+	"fifteenth":   15,
+	"sixteenth":   16,
+	"seventeenth": 17,
+	"eighteenth":  18,
+	"nineteenth":  19,
+	"twentieth":   20,
+	"thirtieth":   30,
+}
+
+// numberWord is a word of a spelled-out number and the separator
+// preceding it.
+type numberWord struct {
+	word   string
+	end    int  // byte offset just past the word
+	hyphen bool // the word is joined to the previous one by a hyphen
+}
+
+// maxNumberWords is the most words read by numberWordsAt, more than the
+// longest number and the word following it.
+const maxNumberWords = 12
+
+// numberWordsAt returns the words at the start of s, up to the first
+// character that is neither a letter, a space nor a hyphen, and at most
+// maxNumberWords of them.
+func numberWordsAt(s string) []numberWord {
+	var words []numberWord
+	i, hyphen := 0, false
+	for i < len(s) && len(words) < maxNumberWords {
+		j := i
+		for j < len(s) {
+			r, width := utf8.DecodeRuneInString(s[j:])
+			if !unicode.IsLetter(r) {
+				break
+			}
+			j += width
+		}
+		if j == i {
+			break
+		}
+		words = append(words, numberWord{strings.ToLower(s[i:j]), j, hyphen})
+		i, hyphen = j, false
+		switch {
+		case i < len(s) && s[i] == '-':
+			i++
+			hyphen = true
+		case i < len(s) && unicode.IsSpace(rune(s[i])):
+			for i < len(s) && unicode.IsSpace(rune(s[i])) {
+				i++
+			}
+		default:
+			return words
+		}
+	}
+	return words
+}
+
+// parseNumberWords returns the length and value of the spelled-out number
+// at the start of s, as in "forty-five", "a hundred and twenty",
+// "a couple of" or "a dozen", and whether it is an ordinal, as in
+// "first" or "thirty first". The length is zero if s does not start with
+// a number other than a lone "a" or "an".
+func parseNumberWords(s string) (n, value int, ordinal bool) {
+	words := numberWordsAt(s)
+	total, current := 0, 0
+	article := false // the number began with "a" or "an"
+	last := ""       // kind of the last word read
+	for i, w := range words {
+		if w.hyphen && last != "tens" {
+			break
+		}
+		if v, ok := numberWords[w.word]; ok {
+			if last != "" && last != "tens" && last != "scale" && last != "and" || last == "tens" && v >= 10 {
+				break
+			}
+			current += v
+			n, last = w.end, "unit"
+			continue
+		}
+		if v, ok := tensWords[w.word]; ok {
+			if last != "" && last != "scale" && last != "and" || current%100 != 0 {
+				break
+			}
+			current += v
+			n, last = w.end, "tens"
+			continue
+		}
+		v, ok := ordinalWords[w.word]
+		if w.word == "second" && (w.hyphen || !article && i+1 < len(words) && ordinalFollows(words[i+1].word)) {
+			v, ok = 2, true
+		}
+		if ok {
+			if last == "tens" && v >= 10 || last != "" && last != "tens" && last != "scale" && last != "and" {
+				break
+			}
+			return w.end, total + current + v, true
+		}
+		switch w.word {
+		case "a", "an":
+			if i > 0 {
+				return n, total + current, false
+			}
+			article = true
+			continue
+		case "and":
+			// Only within a number, as in "a hundred and five".
+			if last != "scale" || i+1 == len(words) {
+				break
+			}
+			last = "and"
+			continue
+		case "hundred", "thousand":
+			if last == "" && !article || last == "scale" || last == "and" {
+				break
+			}
+			if current == 0 {
+				current = 1
+			}
+			if w.word == "hundred" {
+				current *= 100
+			} else {
+				total += current * 1000
+				current = 0
+			}
+			n, last = w.end, "scale"
+			continue
+		case "dozen":
+			if last == "" && !article || last == "and" {
+				break
+			}
+			if current == 0 {
+				current = 1
+			}
+			return w.end, total + current*12, false
+		case "couple", "few":
+			if i != 1 || !article {
+				break
+			}
+			n, v := w.end, 2
+			if w.word == "few" {
+				v = 3
+			}
+			if i+1 < len(words) && words[i+1].word == "of" && !words[i+1].hyphen {
+				n = words[i+1].end
+			}
+			return n, v, false
+		}
+		break
+	}
+	if last == "" {
+		return 0, 0, false
+	}
+	return n, total + current, false
+}
+
+// ordinalFollows reports whether the word w follows an ordinal rather
+// than a unit, as a weekday, "of", "day", "month" or "last" does.
+func ordinalFollows(w string) bool {
+	switch w {
+	case "of", "day", "month", "last":
+		return true
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if w == name || w == name[:3] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package when
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseNumberWords(t *testing.T) {
+	tests := []struct {
+		in      string
+		n       int
+		value   int
+		ordinal bool
+	}{
+		{"one", 3, 1, false},
+		{"twenty minutes", 6, 20, false},
+		{"forty-five seconds", 10, 45, false},
+		{"Forty Five", 10, 45, false},
+		{"a hundred days", 9, 100, false},
+		{"one hundred and twenty", 22, 120, false},
+		{"a hundred and days", 9, 100, false},
+		{"two thousand and six", 20, 2006, false},
+		{"a couple of days", 11, 2, false},
+		{"a couple days", 8, 2, false},
+		{"a few days", 5, 3, false},
+		{"a dozen", 7, 12, false},
+		{"two dozen", 9, 24, false},
+		{"first", 5, 1, true},
+		{"twenty-third", 12, 23, true},
+		{"thirty first of March", 12, 31, true},
+		{"twenty-second", 13, 22, true},
+		{"thirty second", 6, 30, false},
+		{"ten thirty", 3, 10, false},
+		{"five-day", 4, 5, false},
+		{"second", 0, 0, false},
+		{"second Tuesday", 6, 2, true},
+		{"second of March", 6, 2, true},
+		{"a second of", 0, 0, false},
+		{"a day", 0, 0, false},
+		{"couple of days", 0, 0, false},
+		{"dozen", 0, 0, false},
+	}
+	for _, tt := range tests {
+		n, value, ordinal := parseNumberWords(tt.in)
+		if n != tt.n || value != tt.value || ordinal != tt.ordinal {
+			t.Errorf("parseNumberWords(%q)\nhave %d, %d, %t\nwant %d, %d, %t", tt.in, n, value, ordinal, tt.n, tt.value, tt.ordinal)
+		}
+	}
+}
+
+func TestParseNumbers(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	tests := []testcase{
+		{
+			"twenty minutes ago",
+			time.Date(2006, time.January, 2, 14, 44, 5, 0, loc),
+		},
+		{
+			"forty-five seconds",
+			time.Date(2006, time.January, 2, 15, 4, 50, 0, loc),
+		},
+		{
+			"a hundred days",
+			time.Date(2006, time.April, 12, 15, 4, 5, 0, loc),
+		},
+		{
+			"a couple of days ago",
+			time.Date(2005, time.December, 31, 15, 4, 5, 0, loc),
+		},
+		{
+			"3 days and a few hours",
+			time.Date(2006, time.January, 5, 18, 4, 5, 0, loc),
+		},
+		{
+			"now + a dozen days",
+			time.Date(2006, time.January, 14, 15, 4, 5, 0, loc),
+		},
+		{
+			"thirty first of March",
+			time.Date(2006, time.March, 31, 0, 0, 0, 0, loc),
+		},
+		{
+			"on the twenty-third at noon",
+			time.Date(2006, time.January, 23, 12, 0, 0, 0, loc),
+		},
+		{
+			"first Monday of March",
+			time.Date(2006, time.March, 6, 0, 0, 0, 0, loc),
+		},
+		{
+			"March twenty-second",
+			time.Date(2006, time.March, 22, 0, 0, 0, 0, loc),
+		},
+		{
+			"the second Tuesday of March",
+			time.Date(2006, time.March, 14, 0, 0, 0, 0, loc),
+		},
+		{
+			"second of March",
+			time.Date(2006, time.March, 2, 0, 0, 0, 0, loc),
+		},
+		{
+			"one second ago",
+			time.Date(2006, time.January, 2, 15, 4, 4, 0, loc),
+		},
+		{
+			"thirty second ago",
+			time.Date(2006, time.January, 2, 15, 3, 35, 0, loc),
+		},
+	}
+	for _, tc := range tests {
+		tc.apply(t, now)
+	}
+}
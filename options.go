@@ -0,0 +1,227 @@
+package when
+
+import "time"
+
+// Bias controls the direction in which an anchor that omits its year,
+// month or week is resolved relative to the reference time. It applies
+// to named months and weekdays, days of the month, nth weekdays of a
+// month, holidays, quarters, halves and fiscal years, numeric dates
+// without a year, the start and end of any of these, and to the order
+// of the endpoints of an interval. A named weekday is never today, so
+// "Friday" on a Friday is a week away in either direction, while any
+// other anchor containing the reference time is kept with a bias to the
+// past, as in "January" on January 2nd.
+type Bias int
+
+const (
+	// Future resolves an anchor to its next occurrence.
+	Future Bias = iota
+	// Past resolves an anchor to its most recent occurrence.
+	Past
+)
+
+// NextPolicy controls the meaning of "next" before a month or weekday.
+type NextPolicy int
+
+const (
+	// NextPeriod resolves "next March" to March of the following year
+	// and "next Friday" to Friday of the following week.
+	NextPeriod NextPolicy = iota
+	// NextOccurrence resolves "next" to the first occurrence after the
+	// reference time, like "upcoming".
+	NextOccurrence
+)
+
+// DateOrder is the order of the fields of a numeric date that does not
+// start with a four digit year, as in "01/02/2006".
+type DateOrder int
+
+const (
+	// MDY reads numeric dates month first, so "01/02/2006" is January 2nd.
+	MDY DateOrder = iota
+	// DMY reads numeric dates day first, so "01/02/2006" is February 1st.
+	DMY
+	// YMD reads numeric dates year first, so "06/01/02" is January 2nd
+	// 2006. Dates ending in a four digit year are read month first, as
+	// are dates of two fields, so "12/25" is December 25th.
+	YMD
+)
+
+// Parser parses expressions according to a set of policies.
+// The zero value is not usable; create one with New.
+type Parser struct {
+	now         func() time.Time
+	loc         *time.Location
+	weekStart   time.Weekday
+	bias        Bias
+	next        NextPolicy
+	strict      bool
+	calendar    Calendar
+	holidays    HolidayProvider
+	fiscal      time.Month
+	locale      *Locale
+	dateOrder   DateOrder
+	strictDates bool
+	pivot       int
+}
+
+// Option configures a Parser.
+type Option func(*Parser)
+
+// New returns a Parser configured by opts. By default expressions are
+// resolved relative to the current time in its location, weeks start on
+// Sunday, anchors are biased to the future, "next" refers to the
+// following period, out of range dates are normalized, business days
+// and working hours are counted Monday to Friday from 9am to 5pm,
+// holidays are the DefaultHolidays, fiscal years start in January,
+// expressions are in English, numeric dates are read month first and
+// two digit years from 69 are in the 1900s.
+func New(opts ...Option) *Parser {
+	p := &Parser{
+		now:       time.Now,
+		weekStart: time.Sunday,
+		bias:      Future,
+		next:      NextPeriod,
+		calendar:  defaultCalendar,
+		holidays:  DefaultHolidays,
+		fiscal:    time.January,
+		dateOrder: MDY,
+		pivot:     69,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// WithReference sets the time expressions are resolved relative to.
+func WithReference(now time.Time) Option {
+	return func(p *Parser) {
+		p.now = func() time.Time { return now }
+	}
+}
+
+// WithLocation sets the location of the derived time. By default the
+// location of the reference time is used.
+func WithLocation(loc *time.Location) Option {
+	return func(p *Parser) {
+		p.loc = loc
+	}
+}
+
+// WithWeekStart sets the first day of the week. Relative weeks, as in
+// "this week" and "the week after next", start on it, and "this Friday"
+// is the Friday of the week containing the reference time.
+func WithWeekStart(w time.Weekday) Option {
+	return func(p *Parser) {
+		p.weekStart = w
+	}
+}
+
+// WithBias sets the direction anchors are resolved in. With a bias to
+// the past, as for searching logs, "Friday" is the most recent Friday
+// and "March 3rd" the most recent March 3rd.
+func WithBias(b Bias) Option {
+	return func(p *Parser) {
+		p.bias = b
+	}
+}
+
+// WithNext sets the meaning of "next" before a month or weekday.
+func WithNext(n NextPolicy) Option {
+	return func(p *Parser) {
+		p.next = n
+	}
+}
+
+// WithStrict rejects dates that are out of range, such as "Feb 30th"
+// or "2006-13-01", rather than normalizing them.
+func WithStrict() Option {
+	return func(p *Parser) {
+		p.strict = true
+	}
+}
+
+// WithCalendar sets the working time counted by business days and
+// working hours.
+func WithCalendar(c Calendar) Option {
+	return func(p *Parser) {
+		p.calendar = c
+	}
+}
+
+// WithHolidays sets the holidays that may be named as dates, as in
+// "Christmas" or "2 days before Easter".
+func WithHolidays(h HolidayProvider) Option {
+	return func(p *Parser) {
+		p.holidays = h
+	}
+}
+
+// WithFiscalYear sets the first month of the fiscal year. Fiscal years
+// are named by the calendar year they end in, so with a start of October
+// "FY2026" runs from October 2025 to September 2026 and "Q1 FY2026" is
+// October to December 2025.
+func WithFiscalYear(start time.Month) Option {
+	return func(p *Parser) {
+		p.fiscal = start
+	}
+}
+
+// WithDateOrder sets the order of the fields of numeric dates, as in
+// "01/02/2006". Dates starting with a four digit year, as in
+// "2006/01/02", are always read year first.
+func WithDateOrder(o DateOrder) Option {
+	return func(p *Parser) {
+		p.dateOrder = o
+	}
+}
+
+// WithStrictDateOrder rejects numeric dates whose day and month could be
+// read either way, as in "01/02/2006", rather than reading them in the
+// date order. Dates such as "13/02/2006" are accepted in either order.
+func WithStrictDateOrder() Option {
+	return func(p *Parser) {
+		p.strictDates = true
+	}
+}
+
+// WithYearPivot sets the pivot of two digit years in numeric dates. Years
+// below the pivot are in the 2000s and others in the 1900s, so with the
+// default of 69 "01/02/68" is in 2068 and "01/02/69" is in 1969.
+func WithYearPivot(pivot int) Option {
+	return func(p *Parser) {
+		p.pivot = pivot
+	}
+}
+
+// expandYear returns the two digit year y in its century.
+func (p *Parser) expandYear(y int) int {
+	if y < p.pivot {
+		return 2000 + y
+	}
+	return 1900 + y
+}
+
+// WithLocale sets the language of expressions, as in German or Spanish.
+// English is understood in every locale. Parsers of different locales
+// may be used side by side to choose the language of each call.
+func WithLocale(l *Locale) Option {
+	return func(p *Parser) {
+		p.locale = l
+	}
+}
+
+// Parse returns the derived time relative to the reference time.
+func (p *Parser) Parse(s string) (time.Time, error) {
+	return p.ParseNow(s, p.now())
+}
+
+// ParseNow returns the derived time relative to now.
+func (p *Parser) ParseNow(s string, now time.Time) (time.Time, error) {
+	e, err := p.ParseExpr(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return p.Eval(e, now)
+}
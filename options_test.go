@@ -0,0 +1,324 @@
+package when
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParserOptions(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	tests := []struct {
+		opts []Option
+		in   string
+		want time.Time
+	}{
+		// bias
+		{
+			[]Option{WithBias(Past)},
+			"Sunday",
+			time.Date(2006, time.January, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithBias(Past)},
+			"Monday",
+			time.Date(2005, time.December, 26, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithBias(Past)},
+			"January",
+			time.Date(2006, time.January, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithBias(Past)},
+			"November",
+			time.Date(2005, time.November, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithBias(Past)},
+			"3rd",
+			time.Date(2005, time.December, 3, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithBias(Past)},
+			"March 14th",
+			time.Date(2005, time.March, 14, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithBias(Past)},
+			"2nd Tuesday of March",
+			time.Date(2005, time.March, 8, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithBias(Past)},
+			"last Tuesday of March",
+			time.Date(2005, time.March, 29, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithBias(Past)},
+			"Friday at 5pm",
+			time.Date(2005, time.December, 30, 17, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithBias(Past)},
+			"the 2nd",
+			time.Date(2006, time.January, 2, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithBias(Past)},
+			"the 2nd at 5pm",
+			time.Date(2005, time.December, 2, 17, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithBias(Past)},
+			"14/3",
+			time.Date(2005, time.March, 14, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithBias(Past), WithDateOrder(DMY)},
+			"14/3",
+			time.Date(2005, time.March, 14, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithBias(Past)},
+			"Independence Day",
+			time.Date(2005, time.July, 4, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithBias(Past)},
+			"Q3",
+			time.Date(2005, time.July, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithBias(Past)},
+			"H1",
+			time.Date(2006, time.January, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithBias(Past)},
+			"end of March",
+			time.Date(2005, time.March, 31, 23, 59, 59, 999999999, loc),
+		},
+		{
+			[]Option{WithBias(Past)},
+			"2 days after Thanksgiving",
+			time.Date(2005, time.November, 26, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithBias(Past)},
+			"next Friday",
+			time.Date(2006, time.January, 13, 0, 0, 0, 0, loc),
+		},
+		// next
+		{
+			[]Option{WithNext(NextOccurrence)},
+			"next Tuesday",
+			time.Date(2006, time.January, 3, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithNext(NextOccurrence)},
+			"next March",
+			time.Date(2006, time.March, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithNext(NextOccurrence)},
+			"next January",
+			time.Date(2007, time.January, 1, 0, 0, 0, 0, loc),
+		},
+		// week start
+		{
+			[]Option{WithWeekStart(time.Monday)},
+			"next Sunday",
+			time.Date(2006, time.January, 15, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithWeekStart(time.Monday)},
+			"next Monday",
+			time.Date(2006, time.January, 9, 0, 0, 0, 0, loc),
+		},
+		// location
+		{
+			[]Option{WithLocation(time.UTC)},
+			"today at noon",
+			time.Date(2006, time.January, 2, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			[]Option{WithLocation(time.UTC)},
+			"2 hours",
+			time.Date(2006, time.January, 3, 0, 4, 5, 0, time.UTC),
+		},
+		// strict
+		{
+			[]Option{WithStrict()},
+			"Feb 28th",
+			time.Date(2006, time.February, 28, 0, 0, 0, 0, loc),
+		},
+		{
+			nil,
+			"Feb 30th",
+			time.Date(2006, time.March, 2, 0, 0, 0, 0, loc),
+		},
+		// fiscal year
+		{
+			[]Option{WithFiscalYear(time.October)},
+			"FY2026",
+			time.Date(2025, time.October, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithFiscalYear(time.July)},
+			"Q2 FY2006",
+			time.Date(2005, time.October, 1, 0, 0, 0, 0, loc),
+		},
+		// date order
+		{
+			nil,
+			"01/02/2006",
+			time.Date(2006, time.January, 2, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithDateOrder(DMY)},
+			"01/02/2006",
+			time.Date(2006, time.February, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithDateOrder(YMD)},
+			"06/01/02",
+			time.Date(2006, time.January, 2, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithDateOrder(YMD)},
+			"01/02/2006",
+			time.Date(2006, time.January, 2, 0, 0, 0, 0, loc),
+		},
+		{
+			nil,
+			"13/02/2006",
+			time.Date(2006, time.February, 13, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithDateOrder(DMY)},
+			"02/13/2006",
+			time.Date(2006, time.February, 13, 0, 0, 0, 0, loc),
+		},
+		{
+			nil,
+			"12/25",
+			time.Date(2006, time.December, 25, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithDateOrder(DMY)},
+			"25-12 at 3pm",
+			time.Date(2006, time.December, 25, 15, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithDateOrder(YMD)},
+			"12/25",
+			time.Date(2006, time.December, 25, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithDateOrder(YMD)},
+			"31/12/99",
+			time.Date(1999, time.December, 31, 0, 0, 0, 0, loc),
+		},
+		{
+			nil,
+			"99/01/02",
+			time.Date(1999, time.January, 2, 0, 0, 0, 0, loc),
+		},
+		{
+			nil,
+			"3/2007",
+			time.Date(2007, time.March, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			nil,
+			"2006/01/02",
+			time.Date(2006, time.January, 2, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithStrictDateOrder()},
+			"13/02/2006",
+			time.Date(2006, time.February, 13, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithStrictDateOrder()},
+			"02/02/06",
+			time.Date(2006, time.February, 2, 0, 0, 0, 0, loc),
+		},
+		// year pivot
+		{
+			nil,
+			"01/02/68",
+			time.Date(2068, time.January, 2, 0, 0, 0, 0, loc),
+		},
+		{
+			nil,
+			"01/02/69",
+			time.Date(1969, time.January, 2, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithYearPivot(50)},
+			"01/02/68",
+			time.Date(1968, time.January, 2, 0, 0, 0, 0, loc),
+		},
+		{
+			[]Option{WithYearPivot(50), WithDateOrder(DMY)},
+			"02.01.49",
+			time.Date(2049, time.January, 2, 0, 0, 0, 0, loc),
+		},
+		// reference
+		{
+			[]Option{WithReference(now.AddDate(0, 0, 1))},
+			"tomorrow",
+			time.Date(2006, time.January, 4, 0, 0, 0, 0, loc),
+		},
+	}
+	for _, tt := range tests {
+		p := New(append([]Option{WithReference(now)}, tt.opts...)...)
+		have, err := p.Parse(tt.in)
+		if err != nil {
+			t.Fatalf("Parse(%q) %v", tt.in, err)
+		}
+		if !have.Equal(tt.want) || have.Location() != tt.want.Location() {
+			t.Errorf("Parse(%q)\nhave %v\nwant %v", tt.in, have, tt.want)
+		}
+	}
+}
+
+func TestParserStrict(t *testing.T) {
+	var tests = []string{
+		"Feb 30th",
+		"30th of February",
+		"2006-13-01",
+		"2006-02-29",
+		"31st of next month",
+		"0th",
+		"13/14/2006",
+	}
+	p := New(WithStrict(), WithReference(time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)))
+	for _, tc := range tests {
+		have, err := p.Parse(tc)
+		if err == nil {
+			t.Errorf("Parse(%q)\nhave %v\nwant parse error", tc, have)
+		}
+	}
+}
+
+func TestParserStrictDateOrder(t *testing.T) {
+	var tests = []string{
+		"01/02/2006",
+		"1/2",
+		"06/01/02",
+		"on 01/02/2006 at 3pm",
+	}
+	p := New(WithStrictDateOrder(), WithReference(time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)))
+	for _, tc := range tests {
+		have, err := p.Parse(tc)
+		if err == nil {
+			t.Errorf("Parse(%q)\nhave %v\nwant parse error", tc, have)
+		}
+	}
+}
@@ -6,71 +6,153 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 type parser struct {
+	eof    int // byte offset of the end of input
 	pos    int
+	mark   int // byte offset of the date being parsed
 	tokens []token
-	now    time.Time
-	lhs    []lhsFn
-	rhs    time.Time
-	sub    bool
-	date   bool
-	time   bool
+	expr   *Expr
+	cfg    *Parser
+	order  WordOrder
 }
 
+var defaultParser = New()
+
 // Parse returns the derived time.
 func Parse(s string) (time.Time, error) {
-	now := time.Now()
-	return ParseNow(s, now)
+	return defaultParser.Parse(s)
 }
 
 // ParseNow returns the derived time relative to now.
 func ParseNow(s string, now time.Time) (time.Time, error) {
-	var t time.Time
-	tokens, err := lex(s)
+	return defaultParser.ParseNow(s, now)
+}
+
+// ParseExpr returns the parsed expression.
+func ParseExpr(s string) (*Expr, error) {
+	return defaultParser.ParseExpr(s)
+}
+
+// ParseExpr returns the parsed expression. The expression may be
+// evaluated against any reference time with Eval or EvalRange.
+func (p *Parser) ParseExpr(s string) (*Expr, error) {
+	tokens, err := p.lex(s)
 	if err != nil {
-		return t, err
+		return nil, err
 	}
-	if len(tokens) == 0 {
-		return now, nil
+	e, err := parseTokens(tokens, len(s), p)
+	if err != nil {
+		if e, ok := err.(*ParseError); ok {
+			e.setInput(s)
+		}
+		return nil, err
 	}
-	p := &parser{
-		now:    now,
-		tokens: tokens,
+	e.Input = s
+	return e, nil
+}
+
+// order returns the word order of the locale of the parser.
+func (p *Parser) order() WordOrder {
+	if p.locale == nil {
+		return WordOrder{}
 	}
-	err = p.parseExpr()
+	return p.locale.Order
+}
+
+// parseTokens parses an expression from tokens ending at byte offset eof
+// according to the policies of cfg.
+func parseTokens(tokens []token, eof int, cfg *Parser) (*Expr, error) {
+	p := newParser(tokens, eof, cfg)
+	err := p.parseExpr()
 	if err != nil {
-		return t, err
+		return nil, err
 	}
-	t = p.rhs
-	for _, fn := range p.lhs {
-		t = fn.apply(t, p.sub)
+	return p.expr, nil
+}
+
+// newParser returns the state of a parser of tokens ending at byte
+// offset eof according to the policies of cfg.
+func newParser(tokens []token, eof int, cfg *Parser) *parser {
+	return &parser{
+		eof:    eof,
+		tokens: tokens,
+		expr:   &Expr{},
+		cfg:    cfg,
+		order:  cfg.order(),
 	}
-	return t, nil
 }
 
 func (p *parser) parseExpr() error {
 	t := p.peek()
-	switch t.typ {
-	case tokenEOF:
+	switch {
+	case t.typ == tokenEOF:
 		return nil
-	case tokenDigit:
+	case t.typ == tokenDigit:
 		return p.parseExprDigit()
+	case t.typ == tokenDuration:
+		return p.parseDurationLeftISO(p.next(), false)
+	case t.typ == tokenAgo && p.order.AgoFirst:
+		p.next()
+		return p.parseDurationPrefix(true)
+	case t.typ == tokenKeyword && t.val == "in":
+		p.next()
+		return p.parseDurationPrefix(false)
 	}
 	return p.parseDateTime()
 }
 
+// parseDurationPrefix parses a duration from now following "in", as in
+// "in 2 hours", or following a word for "ago" placed before it, as in
+// "vor 2 Tagen".
+func (p *parser) parseDurationPrefix(sub bool) error {
+	for {
+		var terms []Term
+		var err error
+		t := p.next()
+		switch {
+		case t.typ == tokenDigit, t.typ == tokenKeyword && t.val == "half":
+			terms, err = p.parseQuantity(t, false)
+		case t.typ == tokenDuration:
+			terms, err = parseDurationTerms(t, false)
+		default:
+			return newParseError(t, "unexpected token", tokenDigit, tokenDuration)
+		}
+		if err != nil {
+			return err
+		}
+		p.expr.Terms = append(p.expr.Terms, terms...)
+		t = p.next()
+		switch t.typ {
+		case tokenEOF:
+			p.expr.Sub = sub
+			return nil
+		case tokenOperatorAdd:
+			continue
+		}
+		return newParseError(t, "unexpected token", tokenEOF, tokenOperatorAdd)
+	}
+}
+
 func (p *parser) parseExprDigit() error {
 	d := p.next()
 	t := p.peek()
+	if strings.ContainsAny(d.val, "./") && t.typ != tokenUnit && t.typ != tokenKeyword {
+		return newParseError(t, "unexpected token", tokenUnit)
+	}
 	switch t.typ {
-	case tokenEOF, tokenDateSeparator:
+	case tokenEOF, tokenDateSeparator, tokenOperatorAdd, tokenOperatorSub:
 		return p.parseDateYear(d)
 	case tokenUnit:
 		return p.parseDurationLeftUnit(d, false)
 	case tokenColon:
 		return p.parseDigitColon(d)
+	case tokenMonth:
+		if p.order.DayFirst {
+			return p.parseDigitMonth(d)
+		}
 	case tokenKeyword:
 		return p.parseDigitKeyword(d)
 	case tokenOrdinal:
@@ -80,10 +162,13 @@ func (p *parser) parseExprDigit() error {
 		m := strings.ToLower(t.val)
 		return p.parseDigitTwelveHour(d, m)
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenEOF, tokenDateSeparator, tokenOperatorAdd, tokenOperatorSub, tokenUnit, tokenColon, tokenKeyword, tokenOrdinal, tokenTwelveHour)
 }
 
 func (p *parser) parseDateTime() error {
+	if p.order.QualifierAfter && p.peekQualifierAfter() {
+		return p.parseQualifierAfter()
+	}
 	t := p.peek()
 	switch t.typ {
 	case tokenNow:
@@ -102,15 +187,27 @@ func (p *parser) parseDateTime() error {
 	case tokenDigit:
 		t = p.next()
 		return p.parseDigit(t)
+	case tokenTimestamp:
+		return p.parseTimestamp()
+	case tokenHoliday:
+		return p.parseHoliday()
+	case tokenPeriod:
+		return p.parsePeriod()
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenNow, tokenDate, tokenMonth, tokenWeekday, tokenKeyword, tokenTime, tokenDigit, tokenTimestamp, tokenHoliday, tokenPeriod)
 }
 
 func (p *parser) parseDate() error {
-	p.time = true
-	if p.date {
+	err := p.parseZone()
+	if err != nil {
+		return err
+	}
+	if p.expr.Date != nil {
 		return p.parseDurationRightNext()
 	}
+	if p.order.QualifierAfter && p.peekQualifierAfter() {
+		return p.parseQualifierAfter()
+	}
 	t := p.peek()
 	switch t.typ {
 	case tokenEOF:
@@ -130,48 +227,144 @@ func (p *parser) parseDate() error {
 	case tokenDigit:
 		t = p.next()
 		return p.parseDigit(t)
+	case tokenTimestamp:
+		return p.parseTimestamp()
+	case tokenHoliday:
+		return p.parseHoliday()
+	case tokenPeriod:
+		return p.parsePeriod()
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenEOF, tokenOperatorAdd, tokenOperatorSub, tokenDate, tokenMonth, tokenWeekday, tokenKeyword, tokenDigit, tokenTimestamp, tokenHoliday, tokenPeriod, tokenZone)
 }
 
 func (p *parser) parseDateConst() error {
 	t := p.next()
-	loc := p.now.Location()
-	y, M, d := p.now.Date()
-	h, m, s := p.rhs.Clock()
-	switch t.val {
+	p.mark = t.pos
+	n := &RelativeDay{}
+	switch strings.ToLower(t.val) {
 	case "today":
 	case "tomorrow":
-		d++
+		n.Days = 1
 	case "yesterday":
-		d--
+		n.Days = -1
 	default:
 		return newParseError(t, "unexpected date")
 	}
-	p.rhs = time.Date(y, M, d, h, m, s, 0, loc)
+	n.Span = p.extent()
+	p.expr.Date = n
 	return p.parseTime()
 }
 
 func (p *parser) parseDateKeyword() error {
 	t := p.next()
 	if t.typ != tokenKeyword || t.val != "on" {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenKeyword)
 	}
 	return p.parseKeywordOn()
 }
 
 func (p *parser) parseDateYear(d token) error {
+	if len(d.val) <= 2 && p.peek().typ == tokenDateSeparator {
+		return p.parseNumericDate(d)
+	}
+	p.mark = d.pos
 	y, err := strconv.Atoi(d.val)
 	if err != nil {
-		return err
+		return newParseError(d, err.Error())
+	}
+	n := &CalendarDate{Span: Span{d.pos, d.end}, Year: y}
+	p.expr.Date = n
+	return p.parseDateYearMonth(n)
+}
+
+// parseNumericDate parses a date of two or three numbers that does not
+// start with a four digit year, as in "01/02/2006", "1/2" and "1/2006".
+// The fields are read in the date order of the parser, or where the
+// month or day is out of range in the first of the orders MDY, DMY and
+// YMD in which they are not, so "13/02/2006" is February 13th.
+func (p *parser) parseNumericDate(d token) error {
+	p.mark = d.pos
+	fields := []token{d}
+	for len(fields) < 3 && p.peek().typ == tokenDateSeparator {
+		p.next()
+		t := p.next()
+		if t.typ != tokenDigit {
+			return newParseError(t, "unexpected token", tokenDigit)
+		}
+		fields = append(fields, t)
+	}
+	v := make([]int, len(fields))
+	for i, t := range fields {
+		n, err := strconv.Atoi(t.val)
+		if err != nil {
+			return newParseError(t, err.Error())
+		}
+		v[i] = n
+	}
+	iy, im, id := p.cfg.dateOrder.fields(fields)
+	if err := validDate(fields, v, im, id); err != nil {
+		for _, o := range []DateOrder{MDY, DMY, YMD} {
+			iy, im, id = o.fields(fields)
+			if validDate(fields, v, im, id) == nil {
+				err = nil
+				break
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if p.cfg.strictDates && id >= 0 && v[im] <= 12 && v[id] <= 12 && v[im] != v[id] {
+		return newParseError(span(fields[0], fields[len(fields)-1]), "ambiguous date")
+	}
+	if iy < 0 {
+		p.expr.Date = &DayOfMonth{Span: p.extent(), Day: v[id], Month: MonthRef{Month: time.Month(v[im])}}
+		return p.parseTime()
 	}
-	loc := p.now.Location()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(y, time.January, 1, h, m, s, 0, loc)
-	return p.parseDateYearMonth()
+	n := &CalendarDate{Span: p.extent(), Year: v[iy], Month: time.Month(v[im])}
+	if len(fields[iy].val) <= 2 {
+		n.Year = p.cfg.expandYear(n.Year)
+	}
+	if id >= 0 {
+		n.Day = v[id]
+	}
+	p.expr.Date = n
+	return p.parseTime()
 }
 
-func (p *parser) parseDateYearMonth() error {
+// validDate returns an error if the month v[im] or the day v[id] of the
+// numeric date of the given fields is out of range.
+func validDate(f []token, v []int, im, id int) error {
+	if v[im] < 1 || v[im] > 12 {
+		return newParseError(f[im], "invalid month")
+	}
+	if id >= 0 && (v[id] < 1 || v[id] > 31) {
+		return newParseError(f[id], "invalid day")
+	}
+	return nil
+}
+
+// fields returns the indexes of the year, month and day of a numeric date
+// of the given fields, or -1 for those omitted. Dates of two fields are a
+// month and day, or a month and year if the year has four digits.
+func (o DateOrder) fields(f []token) (y, m, d int) {
+	last := len(f[len(f)-1].val) > 2
+	switch {
+	case len(f) == 2 && last:
+		return 1, 0, -1
+	case len(f) == 2 && o == DMY:
+		return -1, 1, 0
+	case len(f) == 2:
+		return -1, 0, 1
+	case o == YMD && !last:
+		return 0, 1, 2
+	case o == DMY:
+		return 2, 1, 0
+	}
+	return 2, 0, 1
+}
+
+func (p *parser) parseDateYearMonth(n *CalendarDate) error {
 	t := p.peek()
 	switch t.typ {
 	case tokenEOF:
@@ -183,19 +376,18 @@ func (p *parser) parseDateYearMonth() error {
 	}
 	t = p.next()
 	if t.typ != tokenDigit {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenDigit)
 	}
 	M, err := strconv.Atoi(t.val)
 	if err != nil {
-		return err
+		return newParseError(t, err.Error())
 	}
-	loc := p.rhs.Location()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(p.rhs.Year(), time.Month(M), 1, h, m, s, 0, loc)
-	return p.parseDateYearMonthDay()
+	n.Month = time.Month(M)
+	n.Span = p.extent()
+	return p.parseDateYearMonthDay(n)
 }
 
-func (p *parser) parseDateYearMonthDay() error {
+func (p *parser) parseDateYearMonthDay(n *CalendarDate) error {
 	t := p.peek()
 	switch t.typ {
 	case tokenEOF:
@@ -207,22 +399,24 @@ func (p *parser) parseDateYearMonthDay() error {
 	}
 	t = p.next()
 	if t.typ != tokenDigit {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenDigit)
 	}
 	d, err := strconv.Atoi(t.val)
 	if err != nil {
-		return err
+		return newParseError(t, err.Error())
 	}
-	loc := p.rhs.Location()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(p.rhs.Year(), p.rhs.Month(), d, h, m, s, 0, loc)
+	n.Day = d
+	n.Span = p.extent()
 	return p.parseTime()
 }
 
 func (p *parser) parseDigit(d token) error {
+	if strings.ContainsAny(d.val, "./") {
+		return newParseError(d, "unexpected decimal number")
+	}
 	t := p.peek()
 	switch t.typ {
-	case tokenEOF, tokenDateSeparator:
+	case tokenEOF, tokenDateSeparator, tokenOperatorAdd, tokenOperatorSub:
 		return p.parseDateYear(d)
 	case tokenColon:
 		return p.parseDigitColon(d)
@@ -235,14 +429,14 @@ func (p *parser) parseDigit(d token) error {
 		m := strings.ToLower(t.val)
 		return p.parseDigitTwelveHour(d, m)
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenEOF, tokenDateSeparator, tokenOperatorAdd, tokenOperatorSub, tokenColon, tokenKeyword, tokenOrdinal, tokenTwelveHour)
 }
 
 func (p *parser) parseDigitColon(h token) error {
 	p.next()
 	m := p.next()
 	if m.typ != tokenDigit {
-		return newParseError(m, "unexpected token")
+		return newParseError(m, "unexpected token", tokenDigit)
 	}
 	t := p.peek()
 	if t.typ == tokenTwelveHour {
@@ -254,16 +448,11 @@ func (p *parser) parseDigitColon(h token) error {
 func (p *parser) parseDigitColonTwelveHour(h, m token) error {
 	t := p.next()
 	i := strings.ToLower(t.val)
-	loc := p.now.Location()
-	r, err := time.ParseInLocation("3:04pm", h.val+":"+m.val+i, loc)
+	r, err := time.Parse("3:04pm", h.val+":"+m.val+i)
 	if err != nil {
-		return err
+		return newParseError(span(h, t), err.Error())
 	}
-	y, M, d := p.rhs.Date()
-	if p.rhs.IsZero() {
-		y, M, d = p.now.Date()
-	}
-	p.rhs = time.Date(y, M, d, r.Hour(), r.Minute(), 0, 0, loc)
+	p.expr.Clock = &Clock{Hour: r.Hour(), Minute: r.Minute(), Precision: Minute}
 	return p.parseDate()
 }
 
@@ -273,59 +462,101 @@ func (p *parser) parseDigitColonTwentyFourHour(h, m token) error {
 		p.next()
 		return p.parseDigitColonTwentyFourHourWithSeconds(h, m)
 	}
-	loc := p.now.Location()
-	r, err := time.ParseInLocation("15:04", h.val+":"+m.val, loc)
+	r, err := time.Parse("15:04", h.val+":"+m.val)
 	if err != nil {
-		return err
+		return newParseError(span(h, m), err.Error())
 	}
-	y, M, d := p.rhs.Date()
-	if p.rhs.IsZero() {
-		y, M, d = p.now.Date()
-	}
-	p.rhs = time.Date(y, M, d, r.Hour(), r.Minute(), 0, 0, loc)
+	p.expr.Clock = &Clock{Hour: r.Hour(), Minute: r.Minute(), Precision: Minute}
 	return p.parseDate()
 }
 
 func (p *parser) parseDigitColonTwentyFourHourWithSeconds(h, m token) error {
 	s := p.next()
 	if s.typ != tokenDigit {
-		return newParseError(m, "unexpected token")
+		return newParseError(s, "unexpected token", tokenDigit)
 	}
-	loc := p.now.Location()
-	r, err := time.ParseInLocation("15:04:05", h.val+":"+m.val+":"+s.val, loc)
+	sec, frac, _ := strings.Cut(s.val, ".")
+	r, err := time.Parse("15:04:05", h.val+":"+m.val+":"+sec)
 	if err != nil {
-		return err
+		return newParseError(span(h, s), err.Error())
 	}
-	y, M, d := p.rhs.Date()
-	if p.rhs.IsZero() {
-		y, M, d = p.now.Date()
+	c := &Clock{Hour: r.Hour(), Minute: r.Minute(), Second: r.Second(), Precision: Second}
+	if frac != "" {
+		c.Nanosecond, c.Precision = parseFraction(frac)
 	}
-	p.rhs = time.Date(y, M, d, r.Hour(), r.Minute(), r.Second(), 0, loc)
+	p.expr.Clock = c
 	return p.parseDate()
 }
 
 func (p *parser) parseDigitKeyword(d token) error {
 	t := p.next()
 	if t.typ != tokenKeyword {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenKeyword)
 	}
 	switch t.val {
+	case "@", "at":
+		return p.parseDigitKeywordAt(d)
 	case "in":
 		return p.parseDigitKeywordIn(d)
 	case "oclock", "o'clock":
 		return p.parseDigitKeywordOclock(d)
+	case "of":
+		if p.order.DayFirst {
+			return p.parseDigitKeywordOf(d)
+		}
+	case "half", "quarter", "quarters", "and":
+		p.pos--
+		return p.parseDurationLeftUnit(d, false)
+	}
+	return newParseError(t, "unexpected token", tokenKeyword)
+}
+
+// parseDigitDay parses the day of the month d written without an ordinal
+// suffix before the month, as in "3 März" and "3 de marzo".
+func (p *parser) parseDigitDay(d token) (int, error) {
+	p.mark = d.pos
+	n, err := strconv.Atoi(d.val)
+	if err != nil {
+		return 0, newParseError(d, err.Error())
+	}
+	return n, nil
+}
+
+func (p *parser) parseDigitMonth(d token) error {
+	n, err := p.parseDigitDay(d)
+	if err != nil {
+		return err
 	}
-	return newParseError(t, "unexpected token")
+	return p.parseDigitOrdinalMonth(n)
+}
+
+func (p *parser) parseDigitKeywordOf(d token) error {
+	n, err := p.parseDigitDay(d)
+	if err != nil {
+		return err
+	}
+	return p.parseDigitOrdinalOf(n)
+}
+
+func (p *parser) parseDigitKeywordAt(y token) error {
+	err := p.parseDateYear(y)
+	if err != nil {
+		return err
+	}
+	if p.expr.Clock == nil {
+		return newParseError(p.peek(), "unexpected token", tokenTime, tokenDigit)
+	}
+	return nil
 }
 
 func (p *parser) parseDigitKeywordIn(d token) error {
 	t := p.next()
 	if t.typ != tokenKeyword || t.val != "the" {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenKeyword)
 	}
 	t = p.next()
 	if t.typ != tokenKeyword {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenKeyword)
 	}
 	switch t.val {
 	case "morning":
@@ -333,30 +564,31 @@ func (p *parser) parseDigitKeywordIn(d token) error {
 	case "afternoon", "evening":
 		return p.parseDigitTwelveHour(d, "pm")
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenKeyword)
 }
 
 func (p *parser) parseDigitKeywordOclock(d token) error {
 	t := p.next()
 	if t.typ != tokenKeyword || t.val != "in" {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenKeyword)
 	}
 	return p.parseDigitKeywordIn(d)
 }
 
 func (p *parser) parseDigitOrdinal(d token) error {
+	p.mark = d.pos
 	t := p.next()
 	if t.typ != tokenOrdinal {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenOrdinal)
 	}
 	n, err := strconv.Atoi(d.val)
 	if err != nil {
-		return err
+		return newParseError(d, err.Error())
 	}
 	t = p.peek()
 	switch t.typ {
-	case tokenEOF:
-		return p.parseDigitOrdinalEOF(n)
+	case tokenEOF, tokenOperatorAdd, tokenOperatorSub:
+		return p.parseDigitOrdinalTime(n)
 	case tokenKeyword:
 		return p.parseDigitOrdinalKeyword(n)
 	case tokenWeekday:
@@ -364,24 +596,18 @@ func (p *parser) parseDigitOrdinal(d token) error {
 	case tokenMonth:
 		return p.parseDigitOrdinalMonth(n)
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenEOF, tokenOperatorAdd, tokenOperatorSub, tokenKeyword, tokenWeekday, tokenMonth)
 }
 
-func (p *parser) parseDigitOrdinalEOF(d int) error {
-	loc := p.now.Location()
-	y, M, _ := p.now.Date()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(y, M, d, h, m, s, 0, loc)
-	if p.now.Equal(p.rhs) || p.now.After(p.rhs) {
-		p.rhs = p.rhs.AddDate(0, 1, 0)
-	}
-	return nil
+func (p *parser) parseDigitOrdinalTime(d int) error {
+	p.expr.Date = &DayOfMonth{Span: p.extent(), Day: d}
+	return p.parseTime()
 }
 
 func (p *parser) parseDigitOrdinalKeyword(d int) error {
 	t := p.next()
 	if t.typ != tokenKeyword {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenKeyword)
 	}
 	switch t.val {
 	case "@", "at":
@@ -391,17 +617,11 @@ func (p *parser) parseDigitOrdinalKeyword(d int) error {
 	case "last":
 		return p.parseDigitOrdinalLast(d)
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenKeyword)
 }
 
 func (p *parser) parseDigitOrdinalAt(d int) error {
-	loc := p.now.Location()
-	y, M, _ := p.now.Date()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(y, M, d, h, m, s, 0, loc)
-	if p.now.Equal(p.rhs) || p.now.After(p.rhs) {
-		p.rhs = p.rhs.AddDate(0, 1, 0)
-	}
+	p.expr.Date = &DayOfMonth{Span: Span{p.mark, p.tokens[p.pos-2].end}, Day: d}
 	return p.parseTime()
 }
 
@@ -413,27 +633,15 @@ func (p *parser) parseDigitOrdinalOf(d int) error {
 	case tokenMonth:
 		return p.parseDigitOrdinalOfMonth(d)
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenKeyword, tokenMonth)
 }
 
 func (p *parser) parseDigitOrdinalOfKeyword(d int) error {
-	t := p.next()
-	u := p.next()
-	if u.typ != tokenUnit || u.val != "month" {
-		return newParseError(u, "unexpected token")
-	}
-	loc := p.now.Location()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(p.now.Year(), p.now.Month(), d, h, m, s, 0, loc)
-	switch t.val {
-	case "the":
-	case "last":
-		p.rhs = p.rhs.AddDate(0, -1, 0)
-	case "next":
-		p.rhs = p.rhs.AddDate(0, 1, 0)
-	default:
-		return newParseError(t, "unexpected token")
+	r, err := p.parseRelativeMonth()
+	if err != nil {
+		return err
 	}
+	p.expr.Date = &DayOfMonth{Span: p.extent(), Day: d, Month: r}
 	return p.parseTime()
 }
 
@@ -443,12 +651,7 @@ func (p *parser) parseDigitOrdinalOfMonth(d int) error {
 	if err != nil {
 		return err
 	}
-	loc := p.now.Location()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(p.now.Year(), M, d, h, m, s, 0, loc)
-	if p.now.Equal(p.rhs) || p.now.After(p.rhs) {
-		p.rhs = p.rhs.AddDate(1, 0, 0)
-	}
+	p.expr.Date = &DayOfMonth{Span: p.extent(), Day: d, Month: MonthRef{Month: M}}
 	return p.parseTime()
 }
 
@@ -460,13 +663,13 @@ func (p *parser) parseDigitOrdinalLast(d int) error {
 	case tokenWeekday:
 		return p.parseDigitOrdinalLastWeekday(d)
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenUnit, tokenWeekday)
 }
 
 func (p *parser) parseDigitOrdinalLastDay(d int) error {
 	t := p.next()
-	if t.typ != tokenUnit || t.val != "day" {
-		return newParseError(t, "unexpected token")
+	if t.typ != tokenUnit || !strings.EqualFold(t.val, "day") {
+		return newParseError(t, "unexpected token", tokenUnit)
 	}
 	return p.parseDigitOrdinalLastDayOf(d)
 }
@@ -474,7 +677,7 @@ func (p *parser) parseDigitOrdinalLastDay(d int) error {
 func (p *parser) parseDigitOrdinalLastDayOf(d int) error {
 	t := p.next()
 	if t.typ != tokenKeyword || t.val != "of" && t.val != "in" {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenKeyword)
 	}
 	t = p.peek()
 	switch t.typ {
@@ -483,28 +686,15 @@ func (p *parser) parseDigitOrdinalLastDayOf(d int) error {
 	case tokenMonth:
 		return p.parseDigitOrdinalLastDayOfMonth(d)
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenKeyword, tokenMonth)
 }
 
 func (p *parser) parseDigitOrdinalLastDayOfKeyword(d int) error {
-	t := p.next()
-	u := p.next()
-	if u.typ != tokenUnit || u.val != "month" {
-		return newParseError(u, "unexpected token")
-	}
-	loc := p.now.Location()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(p.now.Year(), p.now.Month(), 1, h, m, s, 0, loc)
-	switch t.val {
-	case "the":
-		p.rhs = p.rhs.AddDate(0, 1, -d)
-	case "last":
-		p.rhs = p.rhs.AddDate(0, 0, -d)
-	case "next":
-		p.rhs = p.rhs.AddDate(0, 2, -d)
-	default:
-		return newParseError(t, "unexpected token")
+	r, err := p.parseRelativeMonth()
+	if err != nil {
+		return err
 	}
+	p.expr.Date = &DayOfMonth{Span: p.extent(), Day: -d, Month: r}
 	return p.parseTime()
 }
 
@@ -514,10 +704,7 @@ func (p *parser) parseDigitOrdinalLastDayOfMonth(d int) error {
 	if err != nil {
 		return err
 	}
-	loc := p.now.Location()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(p.now.Year(), M, 1, h, m, s, 0, loc)
-	p.rhs = p.rhs.AddDate(0, 1, -d)
+	p.expr.Date = &DayOfMonth{Span: p.extent(), Day: -d, Month: MonthRef{Month: M}}
 	return p.parseTime()
 }
 
@@ -532,15 +719,7 @@ func (p *parser) parseDigitOrdinalLastWeekday(d int) error {
 		p.next()
 		return p.parseDigitOrdinalLastWeekdayOf(d, w)
 	}
-	loc := p.now.Location()
-	y, M, d := p.now.Date()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(y, M, d, h, m, s, 0, loc)
-	days := int(w - p.rhs.Weekday())
-	if days >= 0 {
-		days -= 7
-	}
-	p.rhs = p.rhs.AddDate(0, 0, days)
+	p.expr.Date = &WeekdayDate{Span: p.extent(), Weekday: w, Qualifier: Last}
 	return p.parseTime()
 }
 
@@ -552,36 +731,15 @@ func (p *parser) parseDigitOrdinalLastWeekdayOf(d int, w time.Weekday) error {
 	case tokenMonth:
 		return p.parseDigitOrdinalLastWeekdayOfMonth(d, w)
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenKeyword, tokenMonth)
 }
 
 func (p *parser) parseDigitOrdinalLastWeekdayOfKeyword(d int, w time.Weekday) error {
-	t := p.next()
-	u := p.next()
-	if u.typ != tokenUnit || u.val != "month" {
-		return newParseError(t, "unexpected token")
-	}
-	loc := p.now.Location()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(p.now.Year(), p.now.Month(), 1, h, m, s, 0, loc)
-	switch t.val {
-	case "the":
-		p.rhs = p.rhs.AddDate(0, 1, -1)
-	case "last":
-		p.rhs = p.rhs.AddDate(0, 0, -1)
-	case "next":
-		p.rhs = p.rhs.AddDate(0, 2, -1)
-	default:
-		return newParseError(t, "unexpected token")
-	}
-	days := int(p.rhs.Weekday() - w)
-	if days < 0 {
-		days += 7
-	}
-	p.rhs = p.rhs.AddDate(0, 0, -days)
-	for i := 0; i < d-1; i++ {
-		p.rhs = p.rhs.AddDate(0, 0, -7)
+	r, err := p.parseRelativeMonth()
+	if err != nil {
+		return err
 	}
+	p.expr.Date = &NthWeekday{Span: p.extent(), N: -d, Weekday: w, Month: r}
 	return p.parseTime()
 }
 
@@ -591,21 +749,7 @@ func (p *parser) parseDigitOrdinalLastWeekdayOfMonth(d int, w time.Weekday) erro
 	if err != nil {
 		return err
 	}
-	loc := p.now.Location()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(p.now.Year(), M, 1, h, m, s, 0, loc)
-	p.rhs = p.rhs.AddDate(0, 1, -1)
-	days := int(p.rhs.Weekday() - w)
-	if days < 0 {
-		days += 7
-	}
-	p.rhs = p.rhs.AddDate(0, 0, -days)
-	for i := 0; i < d-1; i++ {
-		p.rhs = p.rhs.AddDate(0, 0, -7)
-	}
-	if p.now.Equal(p.rhs) || p.now.After(p.rhs) {
-		p.rhs = p.rhs.AddDate(1, 0, 0)
-	}
+	p.expr.Date = &NthWeekday{Span: p.extent(), N: -d, Weekday: w, Month: MonthRef{Month: M}}
 	return p.parseTime()
 }
 
@@ -621,7 +765,7 @@ func (p *parser) parseDigitOrdinalWeekday(d int) error {
 func (p *parser) parseDigitOrdinalWeekdayOf(d int, w time.Weekday) error {
 	t := p.next()
 	if t.typ != tokenKeyword || t.val != "of" && t.val != "in" {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenKeyword)
 	}
 	t = p.peek()
 	switch t.typ {
@@ -630,35 +774,15 @@ func (p *parser) parseDigitOrdinalWeekdayOf(d int, w time.Weekday) error {
 	case tokenMonth:
 		return p.parseDigitOrdinalWeekdayOfMonth(d, w)
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenKeyword, tokenMonth)
 }
 
 func (p *parser) parseDigitOrdinalWeekdayOfKeyword(d int, w time.Weekday) error {
-	t := p.next()
-	u := p.next()
-	if u.typ != tokenUnit || u.val != "month" {
-		return newParseError(u, "unexpected token")
-	}
-	loc := p.now.Location()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(p.now.Year(), p.now.Month(), 1, h, m, s, 0, loc)
-	switch t.val {
-	case "the":
-	case "last":
-		p.rhs = p.rhs.AddDate(0, -1, 0)
-	case "next":
-		p.rhs = p.rhs.AddDate(0, 1, 0)
-	default:
-		return newParseError(t, "unexpected token")
-	}
-	days := int(w - p.rhs.Weekday())
-	if days < 0 {
-		days += 7
-	}
-	p.rhs = p.rhs.AddDate(0, 0, days)
-	for i := 0; i < d-1; i++ {
-		p.rhs = p.rhs.AddDate(0, 0, 7)
+	r, err := p.parseRelativeMonth()
+	if err != nil {
+		return err
 	}
+	p.expr.Date = &NthWeekday{Span: p.extent(), N: d, Weekday: w, Month: r}
 	return p.parseTime()
 }
 
@@ -668,20 +792,7 @@ func (p *parser) parseDigitOrdinalWeekdayOfMonth(d int, w time.Weekday) error {
 	if err != nil {
 		return err
 	}
-	loc := p.now.Location()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(p.now.Year(), M, 1, h, m, s, 0, loc)
-	days := int(w - p.rhs.Weekday())
-	if days < 0 {
-		days += 7
-	}
-	p.rhs = p.rhs.AddDate(0, 0, days)
-	for i := 0; i < d-1; i++ {
-		p.rhs = p.rhs.AddDate(0, 0, 7)
-	}
-	if p.now.Equal(p.rhs) || p.now.After(p.rhs) {
-		p.rhs = p.rhs.AddDate(1, 0, 0)
-	}
+	p.expr.Date = &NthWeekday{Span: p.extent(), N: d, Weekday: w, Month: MonthRef{Month: M}}
 	return p.parseTime()
 }
 
@@ -691,43 +802,36 @@ func (p *parser) parseDigitOrdinalMonth(d int) error {
 	if err != nil {
 		return err
 	}
-	loc := p.now.Location()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(p.now.Year(), M, d, h, m, s, 0, loc)
-	if p.now.Equal(p.rhs) || p.now.After(p.rhs) {
-		p.rhs = p.rhs.AddDate(1, 0, 0)
-	}
+	p.expr.Date = &DayOfMonth{Span: p.extent(), Day: d, Month: MonthRef{Month: M}}
 	return p.parseTime()
 }
 
 func (p *parser) parseDigitTwelveHour(h token, i string) error {
-	r, err := time.ParseInLocation("3pm", h.val+i, p.now.Location())
+	r, err := time.Parse("3pm", h.val+i)
 	if err != nil {
-		return err
-	}
-	y, M, d := p.rhs.Date()
-	if p.rhs.IsZero() {
-		y, M, d = p.now.Date()
+		return newParseError(h, err.Error())
 	}
-	p.rhs = time.Date(y, M, d, r.Hour(), 0, 0, 0, r.Location())
+	p.expr.Clock = &Clock{Hour: r.Hour(), Precision: Hour}
 	return p.parseDate()
 }
 
 func (p *parser) parseDurationLeft(sub bool) error {
 	t := p.next()
-	if t.typ != tokenDigit {
-		return newParseError(t, "unexpected token")
+	switch {
+	case t.typ == tokenDigit, t.typ == tokenKeyword && t.val == "half":
+		return p.parseDurationLeftUnit(t, sub)
+	case t.typ == tokenDuration:
+		return p.parseDurationLeftISO(t, sub)
 	}
-	return p.parseDurationLeftUnit(t, sub)
+	return newParseError(t, "unexpected token", tokenDigit, tokenDuration)
 }
 
 func (p *parser) parseDurationLeftAgo() error {
 	t := p.next()
 	if t.typ != tokenEOF {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenEOF)
 	}
-	p.sub = true
-	p.rhs = p.now
+	p.expr.Sub = true
 	return nil
 }
 
@@ -736,7 +840,7 @@ func (p *parser) parseDurationLeftBefore() error {
 	if t.typ == tokenEOF {
 		return newParseError(t, "unexpected token")
 	}
-	p.sub = true
+	p.expr.Sub = true
 	return p.parseDateTime()
 }
 
@@ -748,11 +852,19 @@ func (p *parser) parseDurationLeftFrom() error {
 	return p.parseDateTime()
 }
 
+func (p *parser) parseDurationLeftISO(d token, sub bool) error {
+	terms, err := parseDurationTerms(d, sub)
+	if err != nil {
+		return err
+	}
+	p.expr.Terms = append(p.expr.Terms, terms...)
+	return p.parseDurationLeftNext()
+}
+
 func (p *parser) parseDurationLeftNext() error {
 	t := p.next()
 	switch t.typ {
 	case tokenEOF:
-		p.rhs = p.now
 		return nil
 	case tokenOperatorAdd:
 		return p.parseDurationLeft(false)
@@ -765,28 +877,36 @@ func (p *parser) parseDurationLeftNext() error {
 	case tokenFrom:
 		return p.parseDurationLeftFrom()
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenEOF, tokenOperatorAdd, tokenOperatorSub, tokenAgo, tokenBefore, tokenFrom)
 }
 
 func (p *parser) parseDurationLeftUnit(d token, sub bool) error {
-	u := p.next()
-	n, err := strconv.Atoi(d.val)
+	terms, err := p.parseQuantity(d, sub)
 	if err != nil {
 		return err
 	}
-	if sub {
-		n *= -1
-	}
-	p.lhs = append(p.lhs, lhsFn{n, u.val})
+	p.expr.Terms = append(p.expr.Terms, terms...)
 	return p.parseDurationLeftNext()
 }
 
 func (p *parser) parseDurationRight(sub bool) error {
 	t := p.next()
-	if t.typ != tokenDigit {
-		return newParseError(t, "unexpected token")
+	switch {
+	case t.typ == tokenDigit, t.typ == tokenKeyword && t.val == "half":
+		return p.parseDurationRightUnit(t, sub)
+	case t.typ == tokenDuration:
+		return p.parseDurationRightISO(t, sub)
 	}
-	return p.parseDurationRightUnit(t, sub)
+	return newParseError(t, "unexpected token", tokenDigit, tokenDuration)
+}
+
+func (p *parser) parseDurationRightISO(d token, sub bool) error {
+	terms, err := parseDurationTerms(d, sub)
+	if err != nil {
+		return err
+	}
+	p.expr.Offsets = append(p.expr.Offsets, terms...)
+	return p.parseDurationRightNext()
 }
 
 func (p *parser) parseDurationRightNext() error {
@@ -799,61 +919,192 @@ func (p *parser) parseDurationRightNext() error {
 	case tokenOperatorSub:
 		return p.parseDurationRight(true)
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenEOF, tokenOperatorAdd, tokenOperatorSub)
 }
 
 func (p *parser) parseDurationRightUnit(d token, sub bool) error {
-	u := p.next()
-	n, err := strconv.Atoi(d.val)
+	terms, err := p.parseQuantity(d, sub)
 	if err != nil {
 		return err
 	}
+	p.expr.Offsets = append(p.expr.Offsets, terms...)
+	return p.parseDurationRightNext()
+}
+
+// parseQuantity parses the unit following the quantity d and returns the
+// terms of the duration, negated if sub is set. The quantity is a whole
+// or decimal number, as in "2 hours" and "1.5 hours", or a fraction, as
+// in "1/2 hour", "half an hour", "a quarter hour" and "three quarters of
+// an hour". A
+// half is added by "and a half" before or after the unit, as in "one and
+// a half hours" and "an hour and a half".
+func (p *parser) parseQuantity(d token, sub bool) ([]Term, error) {
+	num, den := int64(1), int64(1)
+	if d.typ == tokenKeyword {
+		// half a unit
+		den = 2
+		t := p.next()
+		if t.typ != tokenDigit || t.val != "1" {
+			return nil, newParseError(t, "unexpected token", tokenDigit)
+		}
+	} else {
+		var err error
+		num, den, err = parseDecimal(d.val)
+		if err != nil {
+			return nil, newParseError(d, err.Error())
+		}
+		if p.andHalf(true) {
+			num, den = 2*num+den, 2*den
+		}
+	}
+	t := p.peek()
+	if d.typ == tokenDigit && t.typ == tokenKeyword {
+		p.next()
+		switch t.val {
+		case "half":
+			den *= 2
+		case "quarter", "quarters":
+			den *= 4
+		default:
+			return nil, newParseError(t, "unexpected token", tokenKeyword, tokenUnit)
+		}
+		if t = p.peek(); t.typ == tokenKeyword && t.val == "of" {
+			p.next()
+			t = p.next()
+			if t.typ != tokenDigit || t.val != "1" {
+				return nil, newParseError(t, "unexpected token", tokenDigit)
+			}
+		}
+	}
+	u := p.next()
+	if u.typ != tokenUnit {
+		return nil, newParseError(u, "unexpected token", tokenUnit)
+	}
+	if p.andHalf(false) {
+		num, den = 2*num+den, 2*den
+	}
+	g := unitGranularity(u.val)
+	if den > 1 && (g == BusinessDay || g == WorkingHour) {
+		// Working time is only counted in whole units.
+		return nil, newParseError(span(d, u), "fraction of working time")
+	}
+	terms := fractionTerms(num, den, g)
 	if sub {
-		n *= -1
-	}
-	switch u.val {
-	case "y", "year", "years":
-		p.rhs = p.rhs.AddDate(n, 0, 0)
-	case "M", "month", "months":
-		p.rhs = p.rhs.AddDate(0, n, 0)
-	case "w", "week", "weeks":
-		p.rhs = p.rhs.AddDate(0, 0, 7*n)
-	case "d", "day", "days":
-		p.rhs = p.rhs.AddDate(0, 0, n)
-	case "h", "hour", "hours":
-		p.rhs = p.rhs.Add(time.Duration(n) * time.Hour)
-	case "m", "minute", "minutes":
-		p.rhs = p.rhs.Add(time.Duration(n) * time.Minute)
-	case "s", "second", "seconds":
-		p.rhs = p.rhs.Add(time.Duration(n) * time.Second)
-	default:
-		return newParseError(u, "unexpected token")
+		for i := range terms {
+			terms[i].N *= -1
+		}
 	}
-	return p.parseDurationRightNext()
+	return terms, nil
+}
+
+// andHalf reports whether the next tokens are "and a half" followed by
+// a unit if unit is set, as in "one and a half hours", or otherwise by
+// anything else, as in "an hour and a half ago", consuming them if so.
+// The half of "a day and a half hour" counts the unit following it.
+func (p *parser) andHalf(unit bool) bool {
+	if p.pos+2 >= len(p.tokens) {
+		return false
+	}
+	a, n, h := p.tokens[p.pos], p.tokens[p.pos+1], p.tokens[p.pos+2]
+	if a.val != "and" || n.typ != tokenDigit || n.val != "1" || h.typ != tokenKeyword || h.val != "half" {
+		return false
+	}
+	p.pos += 3
+	if (p.peek().typ == tokenUnit) != unit {
+		p.pos -= 3
+		return false
+	}
+	return true
 }
 
 func (p *parser) parseKeyword() error {
 	t := p.next()
 	if t.typ != tokenKeyword {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenKeyword)
 	}
+	p.mark = t.pos
 	switch t.val {
 	case "@", "at":
 		return p.parseKeywordAt()
 	case "on":
 		return p.parseKeywordOn()
+	case "the":
+		return p.parseKeywordThe()
+	case "this":
+		return p.parseKeywordRelative(This, 0)
 	case "last":
-		return p.parseDigitOrdinalLast(1)
+		return p.parseKeywordLast()
+	case "previous", "past":
+		return p.parseKeywordRelative(Last, -1)
 	case "next":
-		return p.parseKeywordNext()
-	case "upcoming":
-		return p.parseKeywordUpcoming()
+		return p.parseKeywordRelative(Next, 1)
+	case "upcoming", "coming":
+		return p.parseKeywordRelative(Upcoming, 1)
 	case "half":
-		return p.parseKeywordHalf()
+		return p.parseKeywordHalf(t)
 	case "quarter":
 		return p.parseKeywordQuarter()
+	case "start", "beginning", "end":
+		return p.parseKeywordBoundary(t)
+	case "eod", "eow", "eom", "eoq", "eoy":
+		return p.parseKeywordEndOf(t)
+	}
+	return newParseError(t, "unexpected token", tokenKeyword)
+}
+
+// parseKeywordBoundary parses the start or end of the period of a date,
+// as in "start of next week", "end of the month" or "end of Q1".
+func (p *parser) parseKeywordBoundary(t token) error {
+	o := p.next()
+	if o.typ != tokenKeyword || o.val != "of" {
+		return newParseError(o, "unexpected token", tokenKeyword)
+	}
+	if d := p.peek(); d.typ == tokenKeyword && d.val == "the" && p.pos+1 < len(p.tokens) {
+		// "the month" is the current month.
+		switch n := p.tokens[p.pos+1]; {
+		case n.typ == tokenUnit, n.typ == tokenKeyword && n.val == "quarter":
+			p.next()
+		}
+	}
+	var err error
+	switch d := p.peek(); {
+	case d.typ == tokenUnit, d.typ == tokenKeyword && d.val == "quarter":
+		p.mark = d.pos
+		err = p.parseRelativePeriod(0)
+	default:
+		err = p.parseDateTime()
 	}
-	return newParseError(t, "unexpected token")
+	if err != nil {
+		return err
+	}
+	d := p.expr.Date
+	if d == nil {
+		return newParseError(span(t, o), "expected date")
+	}
+	p.expr.Date = &BoundaryDate{Span: Span{t.pos, d.span().End}, Date: d, End: t.val == "end"}
+	return nil
+}
+
+// parseKeywordEndOf parses an abbreviation for the end of the current
+// period, as in "EOD" for the end of the day.
+func (p *parser) parseKeywordEndOf(t token) error {
+	var g Granularity
+	switch t.val {
+	case "eod":
+		g = Day
+	case "eow":
+		g = Week
+	case "eom":
+		g = Month
+	case "eoq":
+		g = Quarter
+	case "eoy":
+		g = Year
+	}
+	s := Span{t.pos, t.end}
+	n := &PeriodDate{Span: s, Unit: g, Relative: true}
+	p.expr.Date = &BoundaryDate{Span: s, Date: n, End: true}
+	return p.parseTime()
 }
 
 func (p *parser) parseKeywordAt() error {
@@ -865,92 +1116,156 @@ func (p *parser) parseKeywordAt() error {
 		t = p.next()
 		return p.parseDigit(t)
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenTime, tokenDigit)
 }
 
-func (p *parser) parseKeywordHalf() error {
-	t := p.next()
+func (p *parser) parseKeywordHalf(h token) error {
+	t := p.peek()
+	if t.typ == tokenDigit {
+		return p.parseDurationLeftUnit(h, false)
+	}
+	t = p.next()
 	if t.typ != tokenKeyword || t.val != "past" {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenKeyword, tokenDigit)
 	}
 	return p.parseKeywordHalfPast()
 }
 
 func (p *parser) parseKeywordHalfPast() error {
-	t := p.next()
-	if t.typ != tokenDigit {
-		return newParseError(t, "unexpected token")
-	}
-	err := p.parseDigit(t)
-	if err != nil {
-		return err
-	}
-	p.rhs = p.rhs.Add(30 * time.Minute)
-	return nil
+	return p.parseClockOffset(30)
 }
 
-func (p *parser) parseKeywordNext() error {
+// parseKeywordRelative parses a month, weekday or period qualified by
+// "this", "next", "previous" or "coming". Periods are offset from the one
+// containing the reference time.
+func (p *parser) parseKeywordRelative(q Qualifier, offset int) error {
 	t := p.peek()
 	switch t.typ {
 	case tokenMonth:
-		return p.parseKeywordNextMonth()
+		return p.parseQualifiedMonth(q)
 	case tokenWeekday:
-		return p.parseKeywordNextWeekday()
+		return p.parseQualifiedWeekday(q)
+	case tokenUnit, tokenKeyword:
+		return p.parseRelativePeriod(offset)
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenMonth, tokenWeekday, tokenUnit, tokenKeyword)
 }
 
-func (p *parser) parseKeywordNextMonth() error {
-	t := p.next()
-	M, err := parseMonth(t)
-	if err != nil {
-		return err
+func (p *parser) parseKeywordLast() error {
+	t := p.peek()
+	switch {
+	case t.typ == tokenMonth:
+		return p.parseQualifiedMonth(Last)
+	case t.typ == tokenKeyword && t.val == "quarter":
+		return p.parseRelativePeriod(-1)
+	case t.typ == tokenUnit:
+		// "last day of the month" is a day of the month.
+		p.next()
+		o := p.peek()
+		p.pos--
+		if o.typ != tokenKeyword || o.val != "of" && o.val != "in" {
+			return p.parseRelativePeriod(-1)
+		}
 	}
-	loc := p.now.Location()
-	y, _, _ := p.now.Date()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(y+1, M, 1, h, m, s, 0, loc)
-	return p.parseTime()
+	return p.parseDigitOrdinalLast(1)
 }
 
-func (p *parser) parseKeywordNextWeekday() error {
+// parseRelativePeriod parses the unit of a period offset from the one
+// containing the reference time, as in "next week" or "last quarter".
+func (p *parser) parseRelativePeriod(offset int) error {
 	t := p.next()
-	w, err := parseWeekday(t)
-	if err != nil {
-		return err
+	return p.parseRelativePeriodUnit(t, offset)
+}
+
+func (p *parser) parseRelativePeriodUnit(u token, offset int) error {
+	g := unitGranularity(u.val)
+	switch {
+	case u.typ == tokenKeyword && u.val == "quarter":
+		g = Quarter
+	case u.typ != tokenUnit || g > Second:
+		return newParseError(u, "unexpected token", tokenUnit, tokenKeyword)
+	}
+	p.expr.Date = &PeriodDate{Span: p.extent(), Unit: g, Relative: true, Offset: offset}
+	if g > Day {
+		// "next hour" has no time of day.
+		return p.parseNow()
 	}
-	loc := p.now.Location()
-	y, M, d := p.now.Date()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(y, M, d, h, m, s, 0, loc)
-	p.rhs = p.rhs.AddDate(0, 0, int(w-p.rhs.Weekday()+7))
 	return p.parseTime()
 }
 
-func (p *parser) parseKeywordUpcoming() error {
-	t := p.peek()
+// qualifiers are the keywords qualifying a month, weekday or period and
+// the period offset they refer to.
+var qualifiers = map[string]struct {
+	q      Qualifier
+	offset int
+}{
+	"this":     {This, 0},
+	"next":     {Next, 1},
+	"last":     {Last, -1},
+	"previous": {Last, -1},
+	"past":     {Last, -1},
+	"upcoming": {Upcoming, 1},
+	"coming":   {Upcoming, 1},
+}
+
+// peekQualifierAfter reports whether the next tokens are a month, weekday
+// or unit followed by its qualifier.
+func (p *parser) peekQualifierAfter() bool {
+	if p.pos+1 >= len(p.tokens) {
+		return false
+	}
+	t, k := p.tokens[p.pos], p.tokens[p.pos+1]
+	switch t.typ {
+	case tokenMonth, tokenWeekday, tokenUnit:
+		_, ok := qualifiers[k.val]
+		return ok && k.typ == tokenKeyword
+	}
+	return false
+}
+
+// parseQualifierAfter parses a month, weekday or period followed by its
+// qualifier, as in "el martes pasado" or "la semana próxima".
+func (p *parser) parseQualifierAfter() error {
+	t := p.next()
+	k := p.next()
+	p.mark = t.pos
+	q := qualifiers[k.val]
 	switch t.typ {
+	case tokenMonth:
+		M, err := parseMonth(t)
+		if err != nil {
+			return err
+		}
+		p.expr.Date = &MonthDate{Span: p.extent(), Month: M, Qualifier: q.q}
 	case tokenWeekday:
-		return p.parseKeywordUpcomingWeekday()
+		w, err := parseWeekday(t)
+		if err != nil {
+			return err
+		}
+		p.expr.Date = &WeekdayDate{Span: p.extent(), Weekday: w, Qualifier: q.q}
+	default:
+		return p.parseRelativePeriodUnit(t, q.offset)
 	}
-	return newParseError(t, "unexpected token")
+	return p.parseTime()
 }
 
-func (p *parser) parseKeywordUpcomingWeekday() error {
+func (p *parser) parseQualifiedMonth(q Qualifier) error {
 	t := p.next()
-	w, err := parseWeekday(t)
+	M, err := parseMonth(t)
 	if err != nil {
 		return err
 	}
-	loc := p.now.Location()
-	y, M, d := p.now.Date()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(y, M, d, h, m, s, 0, loc)
-	days := int(w - p.rhs.Weekday())
-	if days <= 0 {
-		days += 7
+	p.expr.Date = &MonthDate{Span: p.extent(), Month: M, Qualifier: q}
+	return p.parseTime()
+}
+
+func (p *parser) parseQualifiedWeekday(q Qualifier) error {
+	t := p.next()
+	w, err := parseWeekday(t)
+	if err != nil {
+		return err
 	}
-	p.rhs = p.rhs.AddDate(0, 0, days)
+	p.expr.Date = &WeekdayDate{Span: p.extent(), Weekday: w, Qualifier: q}
 	return p.parseTime()
 }
 
@@ -966,41 +1281,104 @@ func (p *parser) parseKeywordOn() error {
 		return p.parseMonth()
 	case tokenKeyword:
 		return p.parseKeywordOnThe()
+	case tokenTimestamp:
+		return p.parseTimestamp()
+	case tokenHoliday:
+		return p.parseHoliday()
+	case tokenPeriod:
+		return p.parsePeriod()
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenDigit, tokenWeekday, tokenMonth, tokenKeyword, tokenTimestamp, tokenHoliday, tokenPeriod)
 }
 
 func (p *parser) parseKeywordOnThe() error {
 	t := p.next()
 	if t.typ != tokenKeyword || t.val != "the" {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenKeyword)
 	}
-	t = p.peek()
+	return p.parseKeywordThe()
+}
+
+func (p *parser) parseKeywordThe() error {
+	t := p.peek()
 	switch t.typ {
 	case tokenDigit:
 		return p.parseKeywordOnTheDigit()
 	case tokenKeyword:
+		if t.val == "quarter" {
+			return p.parseRelativePeriod(0)
+		}
 		return p.parseKeywordOnTheLast()
+	case tokenUnit:
+		return p.parseKeywordTheUnit()
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenDigit, tokenKeyword, tokenUnit)
+}
+
+// parseKeywordTheUnit parses "the day after" or "the week before" as a
+// duration of one unit preceding the anchor, unless followed by "next" or
+// "last" alone, as in "the week after next".
+func (p *parser) parseKeywordTheUnit() error {
+	u := p.next()
+	t := p.peek()
+	switch t.typ {
+	case tokenBefore:
+		p.next()
+		if p.peekRelative("last") {
+			p.next()
+			return p.parseRelativePeriodUnit(u, -2)
+		}
+		p.expr.Terms = append(p.expr.Terms, Term{1, unitGranularity(u.val)})
+		return p.parseDurationLeftBefore()
+	case tokenFrom:
+		p.next()
+		if p.peekRelative("next") {
+			p.next()
+			return p.parseRelativePeriodUnit(u, 2)
+		}
+		p.expr.Terms = append(p.expr.Terms, Term{1, unitGranularity(u.val)})
+		return p.parseDurationLeftFrom()
+	}
+	return newParseError(t, "unexpected token", tokenBefore, tokenFrom)
+}
+
+// peekRelative reports whether the next token is the keyword v without a
+// month, weekday or period following it, as in "the week after next" but
+// not "the week after next Friday".
+func (p *parser) peekRelative(v string) bool {
+	t := p.peek()
+	if t.typ != tokenKeyword || t.val != v {
+		return false
+	}
+	p.next()
+	t = p.peek()
+	p.pos--
+	switch t.typ {
+	case tokenMonth, tokenWeekday, tokenUnit:
+		return false
+	case tokenKeyword:
+		return t.val != "quarter"
+	}
+	return true
 }
 
 func (p *parser) parseKeywordOnTheLast() error {
 	t := p.next()
 	if t.typ != tokenKeyword || t.val != "last" {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenKeyword)
 	}
+	p.mark = t.pos
 	return p.parseDigitOrdinalLast(1)
 }
 
 func (p *parser) parseKeywordOnTheDigit() error {
 	d := p.next()
 	if d.typ != tokenDigit {
-		return newParseError(d, "unexpected token")
+		return newParseError(d, "unexpected token", tokenDigit)
 	}
 	t := p.peek()
 	if t.typ != tokenOrdinal {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenOrdinal)
 	}
 	return p.parseDigitOrdinal(d)
 }
@@ -1013,67 +1391,61 @@ func (p *parser) parseKeywordQuarter() error {
 	case "after", "past":
 		return p.parseKeywordQuarterAfter()
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenKeyword)
 }
 
 func (p *parser) parseKeywordQuarterTo() error {
-	t := p.next()
-	if t.typ != tokenDigit {
-		return newParseError(t, "unexpected token")
-	}
-	err := p.parseDigit(t)
-	if err != nil {
-		return err
-	}
-	p.rhs = p.rhs.Add(-15 * time.Minute)
-	return nil
+	return p.parseClockOffset(-15)
 }
 
 func (p *parser) parseKeywordQuarterAfter() error {
+	return p.parseClockOffset(15)
+}
+
+// parseClockOffset parses the hour following "half past", "quarter past"
+// or "quarter to" and moves its clock by the given number of minutes.
+func (p *parser) parseClockOffset(minutes int) error {
 	t := p.next()
 	if t.typ != tokenDigit {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenDigit)
 	}
 	err := p.parseDigit(t)
 	if err != nil {
 		return err
 	}
-	p.rhs = p.rhs.Add(15 * time.Minute)
+	if p.expr.Clock == nil {
+		return newParseError(t, "expected time of day")
+	}
+	p.expr.Clock.add(minutes)
 	return nil
 }
 
 func (p *parser) parseMonth() error {
 	t := p.next()
+	p.mark = t.pos
 	m, err := parseMonth(t)
 	if err != nil {
 		return err
 	}
 	t = p.peek()
-	switch t.typ {
-	case tokenEOF:
-		return p.parseMonthEOF(m)
-	case tokenKeyword:
+	switch {
+	case t.typ == tokenKeyword && t.val == "the":
 		return p.parseMonthThe(m)
-	case tokenDigit:
+	case t.typ == tokenDigit:
 		return p.parseMonthTheDigit(m)
 	}
-	return newParseError(t, "unexpected token")
+	return p.parseMonthTime(m)
 }
 
-func (p *parser) parseMonthEOF(M time.Month) error {
-	loc := p.now.Location()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(p.now.Year(), M, 1, h, m, s, 0, loc)
-	if p.now.Equal(p.rhs) || p.now.After(p.rhs) {
-		p.rhs = p.rhs.AddDate(1, 0, 0)
-	}
-	return nil
+func (p *parser) parseMonthTime(M time.Month) error {
+	p.expr.Date = &MonthDate{Span: p.extent(), Month: M}
+	return p.parseTime()
 }
 
 func (p *parser) parseMonthThe(M time.Month) error {
 	t := p.next()
 	if t.typ != tokenKeyword || t.val != "the" {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenKeyword)
 	}
 	return p.parseMonthTheDigit(M)
 }
@@ -1081,27 +1453,21 @@ func (p *parser) parseMonthThe(M time.Month) error {
 func (p *parser) parseMonthTheDigit(M time.Month) error {
 	d := p.next()
 	if d.typ != tokenDigit {
-		return newParseError(d, "unexpected token")
+		return newParseError(d, "unexpected token", tokenDigit)
 	}
 	t := p.next()
 	if t.typ != tokenOrdinal {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenOrdinal)
 	}
 	n, err := strconv.Atoi(d.val)
 	if err != nil {
-		return err
-	}
-	loc := p.now.Location()
-	h, m, s := p.rhs.Clock()
-	p.rhs = time.Date(p.now.Year(), M, n, h, m, s, 0, loc)
-	if p.now.Equal(p.rhs) || p.now.After(p.rhs) {
-		p.rhs = p.rhs.AddDate(1, 0, 0)
+		return newParseError(d, err.Error())
 	}
+	p.expr.Date = &DayOfMonth{Span: p.extent(), Day: n, Month: MonthRef{Month: M}}
 	return p.parseTime()
 }
 
 func (p *parser) parseNow() error {
-	p.rhs = p.now
 	t := p.next()
 	switch t.typ {
 	case tokenEOF:
@@ -1111,12 +1477,36 @@ func (p *parser) parseNow() error {
 	case tokenOperatorSub:
 		return p.parseDurationRight(true)
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenEOF, tokenOperatorAdd, tokenOperatorSub)
+}
+
+// parseRelativeMonth parses "the month", "this month", "last month" or
+// "next month".
+func (p *parser) parseRelativeMonth() (MonthRef, error) {
+	r := MonthRef{Relative: true}
+	t := p.next()
+	u := p.next()
+	if u.typ != tokenUnit || !strings.EqualFold(u.val, "month") {
+		return r, newParseError(u, "unexpected token", tokenUnit)
+	}
+	switch t.val {
+	case "the", "this":
+	case "last", "previous", "past":
+		r.Offset = -1
+	case "next", "coming", "upcoming":
+		r.Offset = 1
+	default:
+		return r, newParseError(t, "unexpected token")
+	}
+	return r, nil
 }
 
 func (p *parser) parseTime() error {
-	p.date = true
-	if p.time {
+	err := p.parseZone()
+	if err != nil {
+		return err
+	}
+	if p.expr.Clock != nil {
 		return p.parseDurationRightNext()
 	}
 	t := p.peek()
@@ -1135,21 +1525,16 @@ func (p *parser) parseTime() error {
 		t = p.next()
 		return p.parseDigit(t)
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenEOF, tokenOperatorAdd, tokenOperatorSub, tokenTime, tokenKeyword, tokenDigit, tokenZone)
 }
 
 func (p *parser) parseTimeConst() error {
 	t := p.next()
-	loc := p.now.Location()
-	y, M, d := p.rhs.Date()
-	if p.rhs.IsZero() {
-		y, M, d = p.now.Date()
-	}
-	switch t.val {
+	switch strings.ToLower(t.val) {
 	case "midnight":
-		p.rhs = time.Date(y, M, d, 0, 0, 0, 0, loc)
+		p.expr.Clock = &Clock{Hour: 0, Precision: Hour}
 	case "noon":
-		p.rhs = time.Date(y, M, d, 12, 0, 0, 0, loc)
+		p.expr.Clock = &Clock{Hour: 12, Precision: Hour}
 	default:
 		return newParseError(t, "unexpected date")
 	}
@@ -1159,35 +1544,184 @@ func (p *parser) parseTimeConst() error {
 func (p *parser) parseTimeKeyword() error {
 	t := p.next()
 	if t.typ != tokenKeyword {
-		return newParseError(t, "unexpected token")
+		return newParseError(t, "unexpected token", tokenKeyword)
 	}
 	switch t.val {
 	case "@", "at":
 		return p.parseKeywordAt()
 	}
-	return newParseError(t, "unexpected token")
+	return newParseError(t, "unexpected token", tokenKeyword)
+}
+
+func (p *parser) parseTimestamp() error {
+	t := p.next()
+	p.mark = t.pos
+	ts, err := parseTimestamp(t.val)
+	if err != nil {
+		return newParseError(t, err.Error())
+	}
+	switch n := ts.date.(type) {
+	case *CalendarDate:
+		n.Span = p.extent()
+	case *WeekDate:
+		n.Span = p.extent()
+	}
+	p.expr.Date = ts.date
+	if ts.clock != nil {
+		if p.expr.Clock != nil {
+			return newParseError(t, "duplicate time of day")
+		}
+		p.expr.Clock = ts.clock
+	}
+	if ts.loc != nil {
+		if p.expr.Location != nil {
+			return newParseError(t, "duplicate time zone")
+		}
+		p.expr.Location = ts.loc
+	}
+	return p.parseTime()
+}
+
+// parseHoliday parses a named holiday, optionally followed by a year.
+func (p *parser) parseHoliday() error {
+	t := p.next()
+	p.mark = t.pos
+	y, err := p.parseOptionalYear()
+	if err != nil {
+		return err
+	}
+	p.expr.Date = &HolidayDate{Span: p.extent(), Name: t.val, Year: y}
+	return p.parseTime()
+}
+
+// parsePeriod parses a quarter, half or fiscal year, as in "Q3",
+// "Q3 2025", "H1 FY2026" or "FY2026 Q3".
+func (p *parser) parsePeriod() error {
+	t := p.next()
+	p.mark = t.pos
+	n := &PeriodDate{}
+	if strings.HasPrefix(t.val, "FY") {
+		y, err := strconv.Atoi(t.val[2:])
+		if err != nil {
+			return newParseError(t, err.Error())
+		}
+		n.Unit, n.Year, n.Fiscal = Year, y, true
+		if q := p.peek(); q.typ == tokenPeriod && !strings.HasPrefix(q.val, "FY") {
+			p.next()
+			n.Unit, n.N = periodUnit(q.val)
+		}
+	} else {
+		n.Unit, n.N = periodUnit(t.val)
+		if f := p.peek(); f.typ == tokenPeriod && strings.HasPrefix(f.val, "FY") {
+			p.next()
+			y, err := strconv.Atoi(f.val[2:])
+			if err != nil {
+				return newParseError(f, err.Error())
+			}
+			n.Year, n.Fiscal = y, true
+		} else {
+			y, err := p.parseOptionalYear()
+			if err != nil {
+				return err
+			}
+			n.Year = y
+		}
+	}
+	n.Span = p.extent()
+	p.expr.Date = n
+	return p.parseTime()
+}
+
+// periodUnit returns the unit and number of a quarter or half token, as
+// in "Q3" or "H1".
+func periodUnit(v string) (Granularity, int) {
+	if v[0] == 'H' {
+		return Half, int(v[1] - '0')
+	}
+	return Quarter, int(v[1] - '0')
+}
+
+// parseOptionalYear parses the year that may follow a named date, as in
+// "Christmas 2025" or "Q3 2025", or returns zero if there is none. A
+// digit beginning a time of day or a duration is left unread.
+func (p *parser) parseOptionalYear() (int, error) {
+	d := p.peek()
+	if d.typ != tokenDigit || len(d.val) != 4 || !isDigit(d.val[3]) {
+		return 0, nil
+	}
+	p.next()
+	switch p.peek().typ {
+	case tokenColon, tokenTwelveHour, tokenOrdinal, tokenUnit, tokenDateSeparator:
+		p.pos--
+		return 0, nil
+	}
+	y, err := strconv.Atoi(d.val)
+	if err != nil {
+		return 0, newParseError(d, err.Error())
+	}
+	return y, nil
+}
+
+// parseDurationTerms returns the terms of the ISO 8601 duration token d,
+// negated if sub is set.
+func parseDurationTerms(d token, sub bool) ([]Term, error) {
+	terms, err := parseDuration(d.val)
+	if err != nil {
+		return nil, newParseError(d, err.Error())
+	}
+	if sub {
+		for i := range terms {
+			terms[i].N *= -1
+		}
+	}
+	return terms, nil
+}
+
+// parseZone parses the optional time zone of the anchor.
+func (p *parser) parseZone() error {
+	t := p.peek()
+	if t.typ != tokenZone {
+		return nil
+	}
+	p.next()
+	if p.expr.Location != nil {
+		return newParseError(t, "duplicate time zone")
+	}
+	loc, err := loadZone(t.val)
+	if err != nil {
+		return newParseError(t, err.Error())
+	}
+	p.expr.Location = loc
+	return nil
 }
 
 func (p *parser) parseWeekday() error {
 	t := p.next()
+	p.mark = t.pos
 	w, err := parseWeekday(t)
 	if err != nil {
 		return err
 	}
-	loc := p.now.Location()
-	y, M, d := p.now.Date()
-	h, m, s := p.rhs.Clock()
-	days := int(w - p.now.Weekday())
-	if days <= 0 {
-		days += 7
-	}
-	p.rhs = time.Date(y, M, d+days, h, m, s, 0, loc)
+	p.expr.Date = &WeekdayDate{Span: p.extent(), Weekday: w}
 	return p.parseTime()
 }
 
+// extent returns the span from the start of the date being parsed to the
+// end of the last consumed token.
+func (p *parser) extent() Span {
+	i := p.pos
+	if i > len(p.tokens) {
+		i = len(p.tokens)
+	}
+	if i == 0 {
+		return Span{p.mark, p.mark}
+	}
+	return Span{p.mark, p.tokens[i-1].end}
+}
+
 func (p *parser) peek() token {
 	if p.pos >= len(p.tokens) {
-		return token{tokenEOF, ""}
+		return token{tokenEOF, "", p.eof, p.eof}
 	}
 	return p.tokens[p.pos]
 }
@@ -1201,7 +1735,7 @@ func (p *parser) next() token {
 func parseMonth(t token) (time.Month, error) {
 	var m time.Month
 	if t.typ != tokenMonth {
-		return m, newParseError(t, "unexpected token")
+		return m, newParseError(t, "unexpected token", tokenMonth)
 	}
 	switch strings.ToLower(t.val[:3]) {
 	case "jan":
@@ -1234,10 +1768,29 @@ func parseMonth(t token) (time.Month, error) {
 	return m, nil
 }
 
+// parseFraction returns the nanoseconds of the decimal fraction of a
+// second written as the digits s, and the precision they are written to.
+// Digits beyond nanoseconds are ignored.
+func parseFraction(s string) (int, Granularity) {
+	g := Millisecond
+	switch {
+	case len(s) > 6:
+		g = Nanosecond
+	case len(s) > 3:
+		g = Microsecond
+	}
+	ns, scale := 0, int(time.Second)
+	for i := 0; i < len(s) && scale > 1; i++ {
+		scale /= 10
+		ns += int(s[i]-'0') * scale
+	}
+	return ns, g
+}
+
 func parseWeekday(t token) (time.Weekday, error) {
 	var w time.Weekday
 	if t.typ != tokenWeekday {
-		return w, newParseError(t, "unexpected token")
+		return w, newParseError(t, "unexpected token", tokenWeekday)
 	}
 	switch strings.ToLower(t.val[:3]) {
 	case "sun":
@@ -1260,43 +1813,102 @@ func parseWeekday(t token) (time.Weekday, error) {
 	return w, nil
 }
 
-type lhsFn struct {
-	n    int
-	unit string
+// nthWeekday returns the nth weekday w of the month starting at first.
+func nthWeekday(first time.Time, n int, w time.Weekday) time.Time {
+	days := int(w - first.Weekday())
+	if days < 0 {
+		days += 7
+	}
+	return first.AddDate(0, 0, days+7*(n-1))
 }
 
-func (f lhsFn) apply(t time.Time, sub bool) time.Time {
-	if sub {
-		f.n *= -1
+// nthLastWeekday returns the nth last weekday w of the month starting at first.
+func nthLastWeekday(first time.Time, n int, w time.Weekday) time.Time {
+	last := first.AddDate(0, 1, -1)
+	days := int(last.Weekday() - w)
+	if days < 0 {
+		days += 7
 	}
-	switch f.unit {
-	case "y", "year", "years":
-		return t.AddDate(f.n, 0, 0)
-	case "M", "month", "months":
-		return t.AddDate(0, f.n, 0)
-	case "w", "week", "weeks":
-		return t.AddDate(0, 0, 7*f.n)
-	case "d", "day", "days":
-		return t.AddDate(0, 0, f.n)
-	case "h", "hour", "hours":
-		return t.Add(time.Duration(f.n) * time.Hour)
-	case "m", "minute", "minutes":
-		return t.Add(time.Duration(f.n) * time.Minute)
-	case "s", "second", "seconds":
-		return t.Add(time.Duration(f.n) * time.Second)
+	return last.AddDate(0, 0, -days-7*(n-1))
+}
+
+// ParseError describes a failure to parse an expression. Pos and End
+// are the byte offsets of the offending span within Input.
+type ParseError struct {
+	Input    string   // expression being parsed
+	Pos      int      // byte offset of the start of the span
+	End      int      // byte offset just past the span
+	Value    string   // offending text; empty at the end of input
+	Message  string   // description of the failure
+	Expected []string // token kinds that would have been accepted
+}
+
+// Column returns the one-based column, in runes, of the start of the span.
+func (e *ParseError) Column() int {
+	pos, _ := e.bounds()
+	return utf8.RuneCountInString(e.Input[:pos]) + 1
+}
+
+// Caret returns the input with a line of carets beneath the offending span.
+func (e *ParseError) Caret() string {
+	pos, end := e.bounds()
+	n := utf8.RuneCountInString(e.Input[pos:end])
+	if n == 0 {
+		n = 1
+	}
+	return e.Input + "\n" + strings.Repeat(" ", e.Column()-1) + strings.Repeat("^", n)
+}
+
+func (e *ParseError) Error() string {
+	s := fmt.Sprintf("%s at column %d", e.Message, e.Column())
+	if e.Value != "" {
+		s += fmt.Sprintf(", token: %q", e.Value)
 	}
-	return time.Time{}
+	if n := len(e.Expected); n > 0 {
+		s += ", expected "
+		if n > 1 {
+			s += strings.Join(e.Expected[:n-1], ", ") + " or "
+		}
+		s += e.Expected[n-1]
+	}
+	return s
+}
+
+func (e *ParseError) setInput(s string) {
+	e.Input = s
+	pos, end := e.bounds()
+	e.Value = s[pos:end]
 }
 
-type parseError struct {
-	token   token
-	message string
+// bounds returns the span of the error clamped to the input, which may
+// not contain it when the error is of an expression built by hand.
+func (e *ParseError) bounds() (pos, end int) {
+	clamp := func(i, max int) int {
+		if i < 0 {
+			return 0
+		}
+		if i > max {
+			return max
+		}
+		return i
+	}
+	end = clamp(e.End, len(e.Input))
+	return clamp(e.Pos, end), end
 }
 
-func (e parseError) Error() string {
-	return fmt.Sprintf("%s, token: %q", e.message, e.token.val)
+func newParseError(t token, message string, expected ...tokenType) *ParseError {
+	e := &ParseError{
+		Pos:     t.pos,
+		End:     t.end,
+		Message: message,
+	}
+	for _, typ := range expected {
+		e.Expected = append(e.Expected, typ.String())
+	}
+	return e
 }
 
-func newParseError(t token, message string) parseError {
-	return parseError{t, message}
+// span returns a token covering the input from the start of a to the end of b.
+func span(a, b token) token {
+	return token{a.typ, a.val, a.pos, b.end}
 }
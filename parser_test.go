@@ -150,6 +150,22 @@ func TestParse(t *testing.T) {
 			"1 year from now",
 			time.Date(2007, time.January, 2, 15, 4, 5, 0, loc),
 		},
+		{
+			"in 2 hours",
+			time.Date(2006, time.January, 2, 17, 4, 5, 0, loc),
+		},
+		{
+			"in 1 day 2 hours",
+			time.Date(2006, time.January, 3, 17, 4, 5, 0, loc),
+		},
+		{
+			"in half an hour",
+			time.Date(2006, time.January, 2, 15, 34, 5, 0, loc),
+		},
+		{
+			"in P1D",
+			time.Date(2006, time.January, 3, 15, 4, 5, 0, loc),
+		},
 		// lhs (digit, short unit)
 		{
 			"1y",
@@ -220,6 +236,14 @@ func TestParse(t *testing.T) {
 			"today",
 			time.Date(2006, time.January, 2, 0, 0, 0, 0, loc),
 		},
+		{
+			"Today",
+			time.Date(2006, time.January, 2, 0, 0, 0, 0, loc),
+		},
+		{
+			"TOMORROW at NOON",
+			time.Date(2006, time.January, 3, 12, 0, 0, 0, loc),
+		},
 		{
 			"tomorrow",
 			time.Date(2006, time.January, 3, 0, 0, 0, 0, loc),
@@ -665,6 +689,353 @@ func TestParseDST(t *testing.T) {
 	}
 }
 
+func TestParseSubsecond(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 123456789, loc)
+	tests := []testcase{
+		{
+			"",
+			now,
+		},
+		{
+			"1 hour ago",
+			time.Date(2006, time.January, 2, 14, 4, 5, 123456789, loc),
+		},
+		{
+			"500ms",
+			time.Date(2006, time.January, 2, 15, 4, 5, 623456789, loc),
+		},
+		{
+			"250 millis ago",
+			time.Date(2006, time.January, 2, 15, 4, 4, 873456789, loc),
+		},
+		{
+			"now + 3us - 2 nanoseconds",
+			time.Date(2006, time.January, 2, 15, 4, 5, 123459787, loc),
+		},
+		{
+			"1 microsecond 1ns",
+			time.Date(2006, time.January, 2, 15, 4, 5, 123457790, loc),
+		},
+		{
+			"15:04:05.250",
+			time.Date(2006, time.January, 2, 15, 4, 5, 250000000, loc),
+		},
+		{
+			"tomorrow at 09:30:00.5 + 1500 ms",
+			time.Date(2006, time.January, 3, 9, 30, 2, 0, loc),
+		},
+		{
+			"18:00:00.000000001",
+			time.Date(2006, time.January, 2, 18, 0, 0, 1, loc),
+		},
+	}
+	for _, tc := range tests {
+		tc.apply(t, now)
+	}
+}
+
+func TestParseBoundary(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	end := func(y int, M time.Month, d int) time.Time {
+		return time.Date(y, M, d+1, 0, 0, 0, 0, loc).Add(-1)
+	}
+	tests := []testcase{
+		{
+			"start of next week",
+			time.Date(2006, time.January, 8, 0, 0, 0, 0, loc),
+		},
+		{
+			"end of month",
+			end(2006, time.January, 31),
+		},
+		{
+			"end of the month",
+			end(2006, time.January, 31),
+		},
+		{
+			"beginning of the year",
+			time.Date(2006, time.January, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			"EOD",
+			end(2006, time.January, 2),
+		},
+		{
+			"eow",
+			end(2006, time.January, 7),
+		},
+		{
+			"EOM",
+			end(2006, time.January, 31),
+		},
+		{
+			"eoq",
+			end(2006, time.March, 31),
+		},
+		{
+			"EOY",
+			end(2006, time.December, 31),
+		},
+		{
+			"end of Q2 2025",
+			end(2025, time.June, 30),
+		},
+		{
+			"start of the quarter",
+			time.Date(2006, time.January, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			"end of March at 5pm",
+			time.Date(2006, time.March, 31, 17, 0, 0, 0, loc),
+		},
+		// The period containing now is kept.
+		{
+			"end of Q1",
+			end(2006, time.March, 31),
+		},
+		{
+			"start of Q1",
+			time.Date(2006, time.January, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			"end of January",
+			end(2006, time.January, 31),
+		},
+		{
+			"end of January at 5pm",
+			time.Date(2006, time.January, 31, 17, 0, 0, 0, loc),
+		},
+		{
+			"end of Monday",
+			end(2006, time.January, 2),
+		},
+		{
+			"end of the 2nd",
+			end(2006, time.January, 2),
+		},
+		{
+			"end of H1",
+			end(2006, time.June, 30),
+		},
+		{
+			"end of the 1st",
+			end(2006, time.February, 1),
+		},
+		{
+			"start of February",
+			time.Date(2006, time.February, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			"end of tomorrow",
+			end(2006, time.January, 3),
+		},
+		{
+			"end of 2007",
+			end(2007, time.December, 31),
+		},
+		{
+			"2 days before end of month",
+			end(2006, time.January, 29),
+		},
+		{
+			"end of next month + 1 day",
+			end(2006, time.March, 1),
+		},
+		{
+			"start of last week at 9am",
+			time.Date(2005, time.December, 25, 9, 0, 0, 0, loc),
+		},
+		// relative periods
+		{
+			"next week",
+			time.Date(2006, time.January, 8, 0, 0, 0, 0, loc),
+		},
+		{
+			"last month",
+			time.Date(2005, time.December, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			"Next year",
+			time.Date(2007, time.January, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			"this week",
+			time.Date(2006, time.January, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			"Next Friday",
+			time.Date(2006, time.January, 13, 0, 0, 0, 0, loc),
+		},
+	}
+	for _, tc := range tests {
+		tc.apply(t, now)
+	}
+}
+
+func TestParseRelative(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	tests := []testcase{
+		{
+			"this week",
+			time.Date(2006, time.January, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			"This Friday",
+			time.Date(2006, time.January, 6, 0, 0, 0, 0, loc),
+		},
+		{
+			"this sunday",
+			time.Date(2006, time.January, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			"last tuesday",
+			time.Date(2005, time.December, 27, 0, 0, 0, 0, loc),
+		},
+		{
+			"previous friday",
+			time.Date(2005, time.December, 30, 0, 0, 0, 0, loc),
+		},
+		{
+			"coming friday",
+			time.Date(2006, time.January, 6, 0, 0, 0, 0, loc),
+		},
+		{
+			"the week after next",
+			time.Date(2006, time.January, 15, 0, 0, 0, 0, loc),
+		},
+		{
+			"the week before last",
+			time.Date(2005, time.December, 18, 0, 0, 0, 0, loc),
+		},
+		{
+			"the day after next",
+			time.Date(2006, time.January, 4, 0, 0, 0, 0, loc),
+		},
+		{
+			"the month after next at noon",
+			time.Date(2006, time.March, 1, 12, 0, 0, 0, loc),
+		},
+		{
+			"the week after next friday",
+			time.Date(2006, time.January, 20, 0, 0, 0, 0, loc),
+		},
+		{
+			"the week after next month",
+			time.Date(2006, time.February, 8, 0, 0, 0, 0, loc),
+		},
+		{
+			"this year",
+			time.Date(2006, time.January, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			"past year",
+			time.Date(2005, time.January, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			"previous month",
+			time.Date(2005, time.December, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			"coming month",
+			time.Date(2006, time.February, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			"this quarter",
+			time.Date(2006, time.January, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			"this March",
+			time.Date(2006, time.March, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			"last march",
+			time.Date(2005, time.March, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			"last january",
+			time.Date(2005, time.January, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			"coming january",
+			time.Date(2007, time.January, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			"next hour",
+			time.Date(2006, time.January, 2, 16, 0, 0, 0, loc),
+		},
+		{
+			"this minute",
+			time.Date(2006, time.January, 2, 15, 4, 0, 0, loc),
+		},
+		{
+			"last hour + 5 minutes",
+			time.Date(2006, time.January, 2, 14, 5, 0, 0, loc),
+		},
+		{
+			"the 4th of this month",
+			time.Date(2006, time.January, 4, 0, 0, 0, 0, loc),
+		},
+		{
+			"the last day of previous month",
+			time.Date(2005, time.December, 31, 0, 0, 0, 0, loc),
+		},
+	}
+	for _, tc := range tests {
+		tc.apply(t, now)
+	}
+}
+
+func TestParseRelativeWeekStart(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	p := New(WithWeekStart(time.Monday))
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{
+			"this week",
+			time.Date(2006, time.January, 2, 0, 0, 0, 0, loc),
+		},
+		{
+			"this sunday",
+			time.Date(2006, time.January, 8, 0, 0, 0, 0, loc),
+		},
+		{
+			"next week",
+			time.Date(2006, time.January, 9, 0, 0, 0, 0, loc),
+		},
+		{
+			"the week before last",
+			time.Date(2005, time.December, 19, 0, 0, 0, 0, loc),
+		},
+	}
+	for _, tt := range tests {
+		have, err := p.ParseNow(tt.in, now)
+		if err != nil {
+			t.Fatalf("ParseNow(%q) %v", tt.in, err)
+		}
+		if !have.Equal(tt.want) {
+			t.Errorf("ParseNow(%q)\nhave %v\nwant %v", tt.in, have, tt.want)
+		}
+	}
+}
+
 func TestParserError(t *testing.T) {
 	var tests = []string{
 		"/",
@@ -695,6 +1066,28 @@ func TestParserError(t *testing.T) {
 		"on the 14th of March the 14th at noon",
 		"at noon on March the 14th at 4pm",
 		"at noon tomorrow at 4pm",
+		"end of",
+		"end of 3pm",
+		"start in March",
+		"end of next",
+		"this",
+		"next hour at 3pm",
+		"past 3pm",
+		"in",
+		"in 2 hours ago",
+		"in tomorrow",
+		"3 march",
+		"3 of march",
+		"01/02/",
+		"01/x",
+		"0/1/2006",
+		"13/13/2006",
+		"1/32",
+		"45/45/45",
+		"EOD tomorrow",
+		"the day",
+		"the second",
+		"the week at noon",
 	}
 	now := time.Now()
 	for _, tc := range tests {
@@ -704,3 +1097,67 @@ func TestParserError(t *testing.T) {
 		}
 	}
 }
+
+func TestParseErrorPosition(t *testing.T) {
+	tests := []struct {
+		in       string
+		pos, end int
+		expected []string
+		caret    string
+	}{
+		{
+			"1 year2 months",
+			6, 7,
+			nil,
+			"1 year2 months\n      ^",
+		},
+		{
+			"at noon on the 14th noon",
+			20, 24,
+			[]string{"end of input", "addition operator", "subtraction operator", "keyword", "weekday", "month"},
+			"at noon on the 14th noon\n                    ^^^^",
+		},
+		{
+			"1 year before",
+			13, 13,
+			nil,
+			"1 year before\n             ^",
+		},
+		{
+			"4th of the week",
+			11, 15,
+			[]string{"unit"},
+			"4th of the week\n           ^^^^",
+		},
+		{
+			"13pm",
+			0, 2,
+			nil,
+			"13pm\n^^",
+		},
+		{
+			"1 year ago 4pm",
+			11, 12,
+			[]string{"end of input"},
+			"1 year ago 4pm\n           ^",
+		},
+	}
+	now := time.Now()
+	for _, tt := range tests {
+		_, err := ParseNow(tt.in, now)
+		e, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("Parse(%q)\nhave %v\nwant *ParseError", tt.in, err)
+			continue
+		}
+		if e.Pos != tt.pos || e.End != tt.end {
+			t.Errorf("Parse(%q) span\nhave %d:%d\nwant %d:%d", tt.in, e.Pos, e.End, tt.pos, tt.end)
+		}
+		if tt.expected != nil && !reflect.DeepEqual(e.Expected, tt.expected) {
+			t.Errorf("Parse(%q) expected\nhave %q\nwant %q", tt.in, e.Expected, tt.expected)
+		}
+		if caret := e.Caret(); caret != tt.caret {
+			t.Errorf("Parse(%q) caret\nhave %q\nwant %q", tt.in, caret, tt.caret)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package when
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// maxFractionDigits is the number of decimal places kept in a quantity,
+// enough to express a nanosecond of a second.
+const maxFractionDigits = 9
+
+// parseDecimal returns the value of the decimal number s, such as "2" or
+// "1.5", or of the fraction s, such as "3/4", as the fraction num/den.
+func parseDecimal(s string) (num, den int64, err error) {
+	if n, d, ok := strings.Cut(s, "/"); ok {
+		num, err = strconv.ParseInt(n, 10, 64)
+		if err != nil || len(n) > 9 {
+			return 0, 0, errors.New("invalid quantity")
+		}
+		den, err = strconv.ParseInt(d, 10, 64)
+		if err != nil || den == 0 || len(d) > 9 {
+			return 0, 0, errors.New("invalid quantity")
+		}
+		return num, den, nil
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	if len(frac) > maxFractionDigits {
+		frac = frac[:maxFractionDigits]
+	}
+	num, err = strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil || len(whole) > 9 {
+		return 0, 0, errors.New("invalid quantity")
+	}
+	den = 1
+	for range frac {
+		den *= 10
+	}
+	return num, den, nil
+}
+
+// finer returns the next finer unit than g and the number of them in one
+// g. Clock units are exact. Calendar units are divided by their length in
+// the unit below, taking a month to be 30 days and a day to be 24 hours,
+// so "1.5 months" is 1 month and 15 days.
+func finer(g Granularity) (Granularity, int64) {
+	switch g {
+	case Year:
+		return Month, 12
+	case Month:
+		return Day, 30
+	case Week:
+		return Day, 7
+	case Day:
+		return Hour, 24
+	case Hour:
+		return Minute, 60
+	case Minute:
+		return Second, 60
+	case Second:
+		return Millisecond, 1000
+	case Millisecond:
+		return Microsecond, 1000
+	}
+	return Nanosecond, 1000
+}
+
+// fractionTerms returns num/den units of g as whole terms of g and finer
+// units, rounded to the nearest nanosecond. For example, 3/2 hours is
+// 1 hour and 30 minutes.
+func fractionTerms(num, den int64, g Granularity) []Term {
+	var terms []Term
+	unit := g
+	for {
+		n := num / den
+		num %= den
+		if unit == Nanosecond && 2*num >= den {
+			n++
+		}
+		if n != 0 {
+			terms = append(terms, Term{int(n), unit})
+		}
+		if num == 0 || unit == Nanosecond {
+			break
+		}
+		var f int64
+		unit, f = finer(unit)
+		num *= f
+	}
+	if len(terms) == 0 {
+		return []Term{{0, g}}
+	}
+	return terms
+}
@@ -0,0 +1,156 @@
+package when
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseQuantity(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	tests := []testcase{
+		{
+			"1.5 hours",
+			time.Date(2006, time.January, 2, 16, 34, 5, 0, loc),
+		},
+		{
+			"2.5d",
+			time.Date(2006, time.January, 5, 3, 4, 5, 0, loc),
+		},
+		{
+			"half an hour",
+			time.Date(2006, time.January, 2, 15, 34, 5, 0, loc),
+		},
+		{
+			"half a day ago",
+			time.Date(2006, time.January, 2, 3, 4, 5, 0, loc),
+		},
+		{
+			"a quarter hour",
+			time.Date(2006, time.January, 2, 15, 19, 5, 0, loc),
+		},
+		{
+			"a quarter of an hour ago",
+			time.Date(2006, time.January, 2, 14, 49, 5, 0, loc),
+		},
+		{
+			"three quarters of an hour before noon",
+			time.Date(2006, time.January, 2, 11, 15, 0, 0, loc),
+		},
+		{
+			"a half hour from tomorrow",
+			time.Date(2006, time.January, 3, 0, 30, 0, 0, loc),
+		},
+		{
+			"now + half an hour - 0.5 minutes",
+			time.Date(2006, time.January, 2, 15, 33, 35, 0, loc),
+		},
+		{
+			"1.5 weeks",
+			time.Date(2006, time.January, 13, 3, 4, 5, 0, loc),
+		},
+		{
+			"1.5 months",
+			time.Date(2006, time.February, 17, 15, 4, 5, 0, loc),
+		},
+		{
+			"1.25 years ago",
+			time.Date(2004, time.October, 2, 15, 4, 5, 0, loc),
+		},
+		{
+			"0.25s",
+			time.Date(2006, time.January, 2, 15, 4, 5, 250000000, loc),
+		},
+		{
+			"one and a half hours from now",
+			time.Date(2006, time.January, 2, 16, 34, 5, 0, loc),
+		},
+		{
+			"an hour and a half",
+			time.Date(2006, time.January, 2, 16, 34, 5, 0, loc),
+		},
+		{
+			"in two and a half days",
+			time.Date(2006, time.January, 5, 3, 4, 5, 0, loc),
+		},
+		{
+			"2 hours and a half ago",
+			time.Date(2006, time.January, 2, 12, 34, 5, 0, loc),
+		},
+		{
+			"2 days and a half hour",
+			time.Date(2006, time.January, 4, 15, 34, 5, 0, loc),
+		},
+		{
+			"noon + an hour and a half",
+			time.Date(2006, time.January, 2, 13, 30, 0, 0, loc),
+		},
+		{
+			"1/2 day",
+			time.Date(2006, time.January, 3, 3, 4, 5, 0, loc),
+		},
+		{
+			"3/4 hour ago",
+			time.Date(2006, time.January, 2, 14, 19, 5, 0, loc),
+		},
+		{
+			"now + 1/2h",
+			time.Date(2006, time.January, 2, 15, 34, 5, 0, loc),
+		},
+		{
+			"PT1.5H ago",
+			time.Date(2006, time.January, 2, 13, 34, 5, 0, loc),
+		},
+	}
+	for _, tc := range tests {
+		tc.apply(t, now)
+	}
+}
+
+func TestParseQuantityError(t *testing.T) {
+	tests := []string{
+		"1.5",
+		"at 3.5pm",
+		"half a",
+		"half past",
+		"a quarter",
+		"a quarter of a",
+		"PT1.5H30M",
+		"1 and a half",
+		"1/0 hour",
+		"2 business days and a half",
+	}
+	for _, tt := range tests {
+		have, err := ParseExpr(tt)
+		if err == nil {
+			t.Errorf("ParseExpr(%q)\nhave %v\nwant parse error", tt, have)
+		}
+	}
+}
+
+func TestFractionTerms(t *testing.T) {
+	tests := []struct {
+		num, den int64
+		unit     Granularity
+		want     []Term
+	}{
+		{3, 2, Hour, []Term{{1, Hour}, {30, Minute}}},
+		{1, 2, Hour, []Term{{30, Minute}}},
+		{0, 1, Day, []Term{{0, Day}}},
+		{15, 10, Month, []Term{{1, Month}, {15, Day}}},
+		{1, 3, Minute, []Term{{20, Second}}},
+		{1, 3, Second, []Term{{333, Millisecond}, {333, Microsecond}, {333, Nanosecond}}},
+		{2, 3, Second, []Term{{666, Millisecond}, {666, Microsecond}, {667, Nanosecond}}},
+		{5, 1000000000, Nanosecond, []Term{{0, Nanosecond}}},
+	}
+	for _, tt := range tests {
+		have := fractionTerms(tt.num, tt.den, tt.unit)
+		if !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("fractionTerms(%d, %d, %v)\nhave %v\nwant %v", tt.num, tt.den, tt.unit, have, tt.want)
+		}
+	}
+}
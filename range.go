@@ -0,0 +1,169 @@
+package when
+
+import "time"
+
+// Granularity is the precision of a parsed time.
+type Granularity int
+
+// Granularities ordered from coarsest to finest.
+const (
+	Year Granularity = iota
+	Half
+	Quarter
+	Month
+	Week
+	Day
+	Hour
+	Minute
+	Second
+	Millisecond
+	Microsecond
+	Nanosecond
+)
+
+// Units of working time as defined by a Calendar. They are used in
+// durations and are never the precision of a parsed time.
+const (
+	BusinessDay Granularity = Nanosecond + 1 + iota
+	WorkingHour
+)
+
+func (g Granularity) String() string {
+	switch g {
+	case Year:
+		return "year"
+	case Half:
+		return "half"
+	case Quarter:
+		return "quarter"
+	case Month:
+		return "month"
+	case Week:
+		return "week"
+	case Day:
+		return "day"
+	case Hour:
+		return "hour"
+	case Minute:
+		return "minute"
+	case Second:
+		return "second"
+	case Millisecond:
+		return "millisecond"
+	case Microsecond:
+		return "microsecond"
+	case Nanosecond:
+		return "nanosecond"
+	case BusinessDay:
+		return "business day"
+	case WorkingHour:
+		return "working hour"
+	}
+	return "unknown"
+}
+
+// truncate returns the start of the period of granularity g containing t.
+// Weeks start on weekStart, and years, halves and quarters are counted
+// from the first of yearStart.
+func (g Granularity) truncate(t time.Time, weekStart time.Weekday, yearStart time.Month) time.Time {
+	y, M, d := t.Date()
+	h, m, s := t.Clock()
+	ns := t.Nanosecond()
+	loc := t.Location()
+	months := int(M-yearStart+12) % 12
+	switch g {
+	case Year:
+		return time.Date(y, M-time.Month(months), 1, 0, 0, 0, 0, loc)
+	case Half:
+		return time.Date(y, M-time.Month(months%6), 1, 0, 0, 0, 0, loc)
+	case Quarter:
+		return time.Date(y, M-time.Month(months%3), 1, 0, 0, 0, 0, loc)
+	case Month:
+		return time.Date(y, M, 1, 0, 0, 0, 0, loc)
+	case Week:
+		days := int(t.Weekday()-weekStart+7) % 7
+		return time.Date(y, M, d-days, 0, 0, 0, 0, loc)
+	case Day:
+		return time.Date(y, M, d, 0, 0, 0, 0, loc)
+	case Hour:
+		return time.Date(y, M, d, h, 0, 0, 0, loc)
+	case Minute:
+		return time.Date(y, M, d, h, m, 0, 0, loc)
+	case Second:
+		return time.Date(y, M, d, h, m, s, 0, loc)
+	case Millisecond:
+		ns -= ns % int(time.Millisecond)
+	case Microsecond:
+		ns -= ns % int(time.Microsecond)
+	}
+	return time.Date(y, M, d, h, m, s, ns, loc)
+}
+
+// add returns t advanced by n periods of granularity g.
+func (g Granularity) add(t time.Time, n int) time.Time {
+	switch g {
+	case Year:
+		return t.AddDate(n, 0, 0)
+	case Half:
+		return t.AddDate(0, 6*n, 0)
+	case Quarter:
+		return t.AddDate(0, 3*n, 0)
+	case Month:
+		return t.AddDate(0, n, 0)
+	case Week:
+		return t.AddDate(0, 0, 7*n)
+	case Day:
+		return t.AddDate(0, 0, n)
+	case Hour:
+		return t.Add(time.Duration(n) * time.Hour)
+	case Minute:
+		return t.Add(time.Duration(n) * time.Minute)
+	case Millisecond:
+		return t.Add(time.Duration(n) * time.Millisecond)
+	case Microsecond:
+		return t.Add(time.Duration(n) * time.Microsecond)
+	case Nanosecond:
+		return t.Add(time.Duration(n))
+	}
+	return t.Add(time.Duration(n) * time.Second)
+}
+
+// Range is the period of time an expression refers to. Start is
+// inclusive and End is exclusive.
+type Range struct {
+	Start       time.Time
+	End         time.Time
+	Granularity Granularity
+}
+
+// Contains reports whether t lies within the range.
+func (r Range) Contains(t time.Time) bool {
+	return !t.Before(r.Start) && t.Before(r.End)
+}
+
+// ParseRange returns the derived range.
+func ParseRange(s string) (Range, error) {
+	return defaultParser.ParseRange(s)
+}
+
+// ParseRangeNow returns the derived range relative to now.
+func ParseRangeNow(s string, now time.Time) (Range, error) {
+	return defaultParser.ParseRangeNow(s, now)
+}
+
+// ParseRange returns the derived range relative to the reference time.
+func (p *Parser) ParseRange(s string) (Range, error) {
+	return p.ParseRangeNow(s, p.now())
+}
+
+// ParseRangeNow returns the derived range relative to now. The range
+// spans the period of the anchor at its granularity, such as the whole
+// day for "tomorrow" or the whole month for "March", shifted by any
+// durations in the expression.
+func (p *Parser) ParseRangeNow(s string, now time.Time) (Range, error) {
+	e, err := p.ParseExpr(s)
+	if err != nil {
+		return Range{}, err
+	}
+	return p.EvalRange(e, now)
+}
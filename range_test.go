@@ -0,0 +1,270 @@
+package when
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRange(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	tests := []struct {
+		in   string
+		want Range
+	}{
+		{
+			"",
+			Range{now, now.Add(time.Second), Second},
+		},
+		{
+			"now",
+			Range{now, now.Add(time.Second), Second},
+		},
+		{
+			"6 hours ago",
+			Range{
+				time.Date(2006, time.January, 2, 9, 4, 5, 0, loc),
+				time.Date(2006, time.January, 2, 9, 4, 6, 0, loc),
+				Second,
+			},
+		},
+		{
+			"15:04:05.250",
+			Range{
+				time.Date(2006, time.January, 2, 15, 4, 5, 250000000, loc),
+				time.Date(2006, time.January, 2, 15, 4, 5, 251000000, loc),
+				Millisecond,
+			},
+		},
+		{
+			"2024",
+			Range{
+				time.Date(2024, time.January, 1, 0, 0, 0, 0, loc),
+				time.Date(2025, time.January, 1, 0, 0, 0, 0, loc),
+				Year,
+			},
+		},
+		{
+			"2006-02",
+			Range{
+				time.Date(2006, time.February, 1, 0, 0, 0, 0, loc),
+				time.Date(2006, time.March, 1, 0, 0, 0, 0, loc),
+				Month,
+			},
+		},
+		{
+			"March",
+			Range{
+				time.Date(2006, time.March, 1, 0, 0, 0, 0, loc),
+				time.Date(2006, time.April, 1, 0, 0, 0, 0, loc),
+				Month,
+			},
+		},
+		{
+			"next February",
+			Range{
+				time.Date(2007, time.February, 1, 0, 0, 0, 0, loc),
+				time.Date(2007, time.March, 1, 0, 0, 0, 0, loc),
+				Month,
+			},
+		},
+		{
+			"tomorrow",
+			Range{
+				time.Date(2006, time.January, 3, 0, 0, 0, 0, loc),
+				time.Date(2006, time.January, 4, 0, 0, 0, 0, loc),
+				Day,
+			},
+		},
+		{
+			"last day of the month",
+			Range{
+				time.Date(2006, time.January, 31, 0, 0, 0, 0, loc),
+				time.Date(2006, time.February, 1, 0, 0, 0, 0, loc),
+				Day,
+			},
+		},
+		{
+			"3pm",
+			Range{
+				time.Date(2006, time.January, 2, 15, 0, 0, 0, loc),
+				time.Date(2006, time.January, 2, 16, 0, 0, 0, loc),
+				Hour,
+			},
+		},
+		{
+			"2nd Tuesday of March at noon",
+			Range{
+				time.Date(2006, time.March, 14, 12, 0, 0, 0, loc),
+				time.Date(2006, time.March, 14, 13, 0, 0, 0, loc),
+				Hour,
+			},
+		},
+		{
+			"quarter past 3pm",
+			Range{
+				time.Date(2006, time.January, 2, 15, 15, 0, 0, loc),
+				time.Date(2006, time.January, 2, 15, 16, 0, 0, loc),
+				Minute,
+			},
+		},
+		{
+			"15:04:05",
+			Range{
+				time.Date(2006, time.January, 2, 15, 4, 5, 0, loc),
+				time.Date(2006, time.January, 2, 15, 4, 6, 0, loc),
+				Second,
+			},
+		},
+		// periods
+		{
+			"Q3",
+			Range{
+				time.Date(2006, time.July, 1, 0, 0, 0, 0, loc),
+				time.Date(2006, time.October, 1, 0, 0, 0, 0, loc),
+				Quarter,
+			},
+		},
+		{
+			"next quarter",
+			Range{
+				time.Date(2006, time.April, 1, 0, 0, 0, 0, loc),
+				time.Date(2006, time.July, 1, 0, 0, 0, 0, loc),
+				Quarter,
+			},
+		},
+		{
+			"last quarter",
+			Range{
+				time.Date(2005, time.October, 1, 0, 0, 0, 0, loc),
+				time.Date(2006, time.January, 1, 0, 0, 0, 0, loc),
+				Quarter,
+			},
+		},
+		{
+			"this week",
+			Range{
+				time.Date(2006, time.January, 1, 0, 0, 0, 0, loc),
+				time.Date(2006, time.January, 8, 0, 0, 0, 0, loc),
+				Week,
+			},
+		},
+		{
+			"next hour",
+			Range{
+				time.Date(2006, time.January, 2, 16, 0, 0, 0, loc),
+				time.Date(2006, time.January, 2, 17, 0, 0, 0, loc),
+				Hour,
+			},
+		},
+		{
+			"H2 2025",
+			Range{
+				time.Date(2025, time.July, 1, 0, 0, 0, 0, loc),
+				time.Date(2026, time.January, 1, 0, 0, 0, 0, loc),
+				Half,
+			},
+		},
+		{
+			"FY2026",
+			Range{
+				time.Date(2026, time.January, 1, 0, 0, 0, 0, loc),
+				time.Date(2027, time.January, 1, 0, 0, 0, 0, loc),
+				Year,
+			},
+		},
+		// shifted
+		{
+			"2 days from March",
+			Range{
+				time.Date(2006, time.March, 3, 0, 0, 0, 0, loc),
+				time.Date(2006, time.April, 3, 0, 0, 0, 0, loc),
+				Month,
+			},
+		},
+		{
+			"today + 2 days",
+			Range{
+				time.Date(2006, time.January, 4, 0, 0, 0, 0, loc),
+				time.Date(2006, time.January, 5, 0, 0, 0, 0, loc),
+				Day,
+			},
+		},
+	}
+	for _, tt := range tests {
+		have, err := ParseRangeNow(tt.in, now)
+		if err != nil {
+			t.Fatalf("ParseRange(%q) %v", tt.in, err)
+		}
+		if !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("ParseRange(%q)\nhave %v\nwant %v", tt.in, have, tt.want)
+		}
+	}
+}
+
+func TestParseRangeFiscal(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	p := New(WithFiscalYear(time.October))
+	tests := []struct {
+		in   string
+		want Range
+	}{
+		{
+			"FY2026",
+			Range{
+				time.Date(2025, time.October, 1, 0, 0, 0, 0, loc),
+				time.Date(2026, time.October, 1, 0, 0, 0, 0, loc),
+				Year,
+			},
+		},
+		{
+			"Q1 FY2026",
+			Range{
+				time.Date(2025, time.October, 1, 0, 0, 0, 0, loc),
+				time.Date(2026, time.January, 1, 0, 0, 0, 0, loc),
+				Quarter,
+			},
+		},
+		{
+			"FY26 H2",
+			Range{
+				time.Date(2026, time.April, 1, 0, 0, 0, 0, loc),
+				time.Date(2026, time.October, 1, 0, 0, 0, 0, loc),
+				Half,
+			},
+		},
+		{
+			"Q3",
+			Range{
+				time.Date(2006, time.July, 1, 0, 0, 0, 0, loc),
+				time.Date(2006, time.October, 1, 0, 0, 0, 0, loc),
+				Quarter,
+			},
+		},
+		{
+			"2 weeks before Q2 FY2006",
+			Range{
+				time.Date(2005, time.December, 18, 0, 0, 0, 0, loc),
+				time.Date(2006, time.March, 18, 0, 0, 0, 0, loc),
+				Quarter,
+			},
+		},
+	}
+	for _, tt := range tests {
+		have, err := p.ParseRangeNow(tt.in, now)
+		if err != nil {
+			t.Fatalf("ParseRange(%q) %v", tt.in, err)
+		}
+		if !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("ParseRange(%q)\nhave %v\nwant %v", tt.in, have, tt.want)
+		}
+	}
+}
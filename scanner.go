@@ -0,0 +1,104 @@
+package when
+
+// TokenKind identifies the kind of a token.
+type TokenKind int
+
+// Token kinds.
+const (
+	TokenAgo           = TokenKind(tokenAgo)           // ago
+	TokenBefore        = TokenKind(tokenBefore)        // before
+	TokenColon         = TokenKind(tokenColon)         // :
+	TokenDate          = TokenKind(tokenDate)          // today, tomorrow or yesterday
+	TokenDateSeparator = TokenKind(tokenDateSeparator) // - or / within a date
+	TokenDigit         = TokenKind(tokenDigit)         // 3 or three
+	TokenEOF           = TokenKind(tokenEOF)           // end of input
+	TokenFrom          = TokenKind(tokenFrom)          // from or after
+	TokenKeyword       = TokenKind(tokenKeyword)       // at, on, the, next...
+	TokenMonth         = TokenKind(tokenMonth)         // Jan or January
+	TokenNow           = TokenKind(tokenNow)           // now
+	TokenOperatorAdd   = TokenKind(tokenOperatorAdd)   // +, &, comma, and
+	TokenOperatorSub   = TokenKind(tokenOperatorSub)   // -
+	TokenOrdinal       = TokenKind(tokenOrdinal)       // st, nd, rd or th
+	TokenTime          = TokenKind(tokenTime)          // midnight or noon
+	TokenTwelveHour    = TokenKind(tokenTwelveHour)    // am or pm
+	TokenUnit          = TokenKind(tokenUnit)          // h or hours
+	TokenWeekday       = TokenKind(tokenWeekday)       // Mon or Monday
+	TokenZone          = TokenKind(tokenZone)          // UTC, PST, +05:30 or Europe/London
+	TokenTimestamp     = TokenKind(tokenTimestamp)     // 2006-01-02T15:04:05Z, 2006-W01-2 or 2006-002
+	TokenDuration      = TokenKind(tokenDuration)      // P1Y2M3DT4H5M6S
+	TokenHoliday       = TokenKind(tokenHoliday)       // Christmas or Labor Day
+	TokenPeriod        = TokenKind(tokenPeriod)        // Q3, H1 or FY2026
+)
+
+func (k TokenKind) String() string {
+	return tokenType(k).String()
+}
+
+// Token is a lexical token of an expression.
+type Token struct {
+	Kind  TokenKind
+	Value string // normalized value, such as "1" for "one"
+	Pos   int    // byte offset of the token within the input
+	End   int    // byte offset just past the token
+}
+
+// Scanner splits an expression into tokens using the same rules as the
+// parser. It is intended for tooling such as syntax highlighting.
+type Scanner struct {
+	l     *lexer
+	state stateFn
+	i     int
+	tok   Token
+	err   error
+	eof   bool
+}
+
+// NewScanner returns a Scanner reading from s.
+func NewScanner(s string) *Scanner {
+	return defaultParser.Scanner(s)
+}
+
+// Scanner returns a Scanner reading from s with the holidays and locale
+// of the parser.
+func (p *Parser) Scanner(s string) *Scanner {
+	return &Scanner{l: p.lexer(s), state: readExpr}
+}
+
+// Scan advances the Scanner to the next token, which will then be
+// available through the Token method. The last token of the input is
+// TokenEOF. It returns false when the scan stops, either after the end
+// of the input or at an error.
+func (s *Scanner) Scan() bool {
+	for s.i >= len(s.l.tokens) {
+		if s.state == nil {
+			if s.eof || s.err != nil {
+				return false
+			}
+			s.eof = true
+			n := len(s.l.input)
+			s.tok = Token{TokenEOF, "", n, n}
+			return true
+		}
+		s.state = s.state(s.l)
+	}
+	t := s.l.tokens[s.i]
+	s.i++
+	if t.typ == tokenError {
+		s.err = s.l.error(t)
+		s.state = nil
+		return false
+	}
+	s.tok = Token{TokenKind(t.typ), t.val, t.pos, t.end}
+	return true
+}
+
+// Token returns the most recent token generated by a call to Scan.
+func (s *Scanner) Token() Token {
+	return s.tok
+}
+
+// Err returns the first error encountered by the Scanner. The error
+// is a *ParseError.
+func (s *Scanner) Err() error {
+	return s.err
+}
@@ -0,0 +1,127 @@
+package when
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScanner(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []Token
+	}{
+		{
+			"",
+			[]Token{
+				{TokenEOF, "", 0, 0},
+			},
+		},
+		{
+			"seven weeks from Jan 5th at 4pm",
+			[]Token{
+				{TokenDigit, "7", 0, 5},
+				{TokenUnit, "weeks", 6, 11},
+				{TokenFrom, "from", 12, 16},
+				{TokenMonth, "Jan", 17, 20},
+				{TokenDigit, "5", 21, 22},
+				{TokenOrdinal, "th", 22, 24},
+				{TokenKeyword, "at", 25, 27},
+				{TokenDigit, "4", 28, 29},
+				{TokenTwelveHour, "pm", 29, 31},
+				{TokenEOF, "", 31, 31},
+			},
+		},
+		{
+			"the first",
+			[]Token{
+				{TokenKeyword, "the", 0, 3},
+				{TokenDigit, "1", 4, 9},
+				{TokenOrdinal, "st", 4, 9},
+				{TokenEOF, "", 9, 9},
+			},
+		},
+		{
+			"the twenty-second",
+			[]Token{
+				{TokenKeyword, "the", 0, 3},
+				{TokenDigit, "22", 4, 17},
+				{TokenOrdinal, "nd", 11, 17},
+				{TokenEOF, "", 17, 17},
+			},
+		},
+		{
+			"now - 2d",
+			[]Token{
+				{TokenNow, "now", 0, 3},
+				{TokenOperatorSub, "-", 4, 5},
+				{TokenDigit, "2", 6, 7},
+				{TokenUnit, "d", 7, 8},
+				{TokenEOF, "", 8, 8},
+			},
+		},
+		{
+			"2 days before Good Friday",
+			[]Token{
+				{TokenDigit, "2", 0, 1},
+				{TokenUnit, "days", 2, 6},
+				{TokenBefore, "before", 7, 13},
+				{TokenHoliday, "Good Friday", 14, 25},
+				{TokenEOF, "", 25, 25},
+			},
+		},
+	}
+	for _, tt := range tests {
+		var have []Token
+		s := NewScanner(tt.in)
+		for s.Scan() {
+			have = append(have, s.Token())
+		}
+		if err := s.Err(); err != nil {
+			t.Fatalf("Scan(%q) %v", tt.in, err)
+		}
+		if !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("Scan(%q)\nhave %v\nwant %v", tt.in, have, tt.want)
+		}
+	}
+}
+
+func TestScannerError(t *testing.T) {
+	s := NewScanner("3 days ago % 4")
+	var have []TokenKind
+	for s.Scan() {
+		have = append(have, s.Token().Kind)
+	}
+	want := []TokenKind{TokenDigit, TokenUnit, TokenAgo}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("Scan\nhave %v\nwant %v", have, want)
+	}
+	e, ok := s.Err().(*ParseError)
+	if !ok {
+		t.Fatalf("Err()\nhave %v\nwant *ParseError", s.Err())
+	}
+	if e.Pos != 11 || e.End != 12 {
+		t.Errorf("Err() span\nhave %d:%d\nwant 11:12", e.Pos, e.End)
+	}
+}
+
+func TestParserScanner(t *testing.T) {
+	p := New(WithLocale(Spanish))
+	s := p.Scanner("mañana a las 3pm")
+	var have []Token
+	for s.Scan() {
+		have = append(have, s.Token())
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("Scan %v", err)
+	}
+	want := []Token{
+		{TokenDate, "tomorrow", 0, 7},
+		{TokenKeyword, "at", 8, 9},
+		{TokenDigit, "3", 14, 15},
+		{TokenTwelveHour, "pm", 15, 17},
+		{TokenEOF, "", 17, 17},
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("Scan\nhave %v\nwant %v", have, want)
+	}
+}
@@ -0,0 +1,403 @@
+package when
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a recurring set of times, such as "every monday at 9am".
+type Schedule struct {
+	start     time.Time // occurrences are counted from the period containing start
+	every     int
+	unit      Granularity
+	weekday   time.Weekday
+	ordinal   int // nth weekday of the month, negative counting from the end
+	day       int // day of the month, negative counting from the end
+	clock     bool
+	hour      int
+	minute    int
+	second    int
+	nsec      int
+	weekStart time.Weekday
+}
+
+// maxPeriods bounds the search for an occurrence of a schedule that can
+// never occur, such as the 5th Friday of a month every 12 months.
+const maxPeriods = 10000
+
+// ParseSchedule returns the derived schedule.
+func ParseSchedule(s string) (*Schedule, error) {
+	return defaultParser.ParseSchedule(s)
+}
+
+// ParseScheduleNow returns the derived schedule starting from now.
+func ParseScheduleNow(s string, now time.Time) (*Schedule, error) {
+	return defaultParser.ParseScheduleNow(s, now)
+}
+
+// ParseSchedule returns the derived schedule starting from the reference time.
+func (p *Parser) ParseSchedule(s string) (*Schedule, error) {
+	return p.ParseScheduleNow(s, p.now())
+}
+
+// ParseScheduleNow returns the derived schedule starting from now.
+//
+// Schedules are written as "every [N] unit", optionally followed by
+// "on <weekday>" for weeks or "on the <ordinal>" for months, as
+// "every <weekday>", or as "[on] the [Nth] [last] <weekday|day> of every
+// month". Schedules of days or longer may end with "at <time>" and
+// otherwise occur at midnight. Schedules of hours or shorter count from now.
+func (p *Parser) ParseScheduleNow(s string, now time.Time) (*Schedule, error) {
+	if p.loc != nil {
+		now = now.In(p.loc)
+	}
+	tokens, err := p.lex(s)
+	if err != nil {
+		return nil, err
+	}
+	state := newParser(tokens, len(s), p)
+	sched := &Schedule{
+		start:     now,
+		every:     1,
+		weekStart: p.weekStart,
+	}
+	err = state.parseSchedule(sched)
+	if err != nil {
+		if e, ok := err.(*ParseError); ok {
+			e.setInput(s)
+		}
+		return nil, err
+	}
+	if loc := state.expr.Location; loc != nil {
+		sched.start = sched.start.In(loc)
+	}
+	return sched, nil
+}
+
+func (p *parser) parseSchedule(s *Schedule) error {
+	t := p.peek()
+	if t.typ == tokenKeyword && t.val == "every" {
+		p.next()
+		return p.parseScheduleEvery(s)
+	}
+	if t.typ == tokenKeyword && t.val == "on" {
+		p.next()
+		t = p.peek()
+	}
+	if t.typ == tokenKeyword && t.val == "the" {
+		p.next()
+	}
+	return p.parseScheduleThe(s)
+}
+
+func (p *parser) parseScheduleEvery(s *Schedule) error {
+	t := p.next()
+	switch t.typ {
+	case tokenWeekday:
+		w, err := parseWeekday(t)
+		if err != nil {
+			return err
+		}
+		s.unit = Week
+		s.weekday = w
+		return p.parseScheduleAt(s)
+	case tokenKeyword:
+		if t.val != "other" {
+			return newParseError(t, "unexpected token", tokenDigit, tokenUnit, tokenWeekday)
+		}
+		s.every = 2
+		t = p.next()
+	case tokenDigit:
+		n, err := strconv.Atoi(t.val)
+		if err != nil {
+			return newParseError(t, err.Error())
+		}
+		if n < 1 {
+			return newParseError(t, "schedule must repeat at least once")
+		}
+		s.every = n
+		t = p.next()
+	}
+	if t.typ != tokenUnit {
+		return newParseError(t, "unexpected token", tokenUnit)
+	}
+	s.unit = unitGranularity(t.val)
+	switch s.unit {
+	case Year, Month:
+		s.day = s.start.Day()
+	case Week:
+		s.weekday = s.start.Weekday()
+	case Hour, Minute, Second, Millisecond, Microsecond, Nanosecond:
+		return p.parseScheduleEOF()
+	case BusinessDay, WorkingHour:
+		return newParseError(t, "working time in schedule")
+	}
+	t = p.peek()
+	if t.typ == tokenKeyword && t.val == "on" {
+		p.next()
+		return p.parseScheduleEveryOn(s)
+	}
+	return p.parseScheduleAt(s)
+}
+
+func (p *parser) parseScheduleEveryOn(s *Schedule) error {
+	t := p.next()
+	switch {
+	case t.typ == tokenWeekday && s.unit == Week:
+		w, err := parseWeekday(t)
+		if err != nil {
+			return err
+		}
+		s.weekday = w
+		return p.parseScheduleAt(s)
+	case t.typ == tokenKeyword && t.val == "the" && s.unit == Month:
+		d := p.next()
+		if d.typ != tokenDigit {
+			return newParseError(d, "unexpected token", tokenDigit)
+		}
+		n, err := p.parseScheduleOrdinal(d)
+		if err != nil {
+			return err
+		}
+		s.day = n
+		return p.parseScheduleAt(s)
+	}
+	if s.unit == Week {
+		return newParseError(t, "unexpected token", tokenWeekday)
+	}
+	return newParseError(t, "unexpected token", tokenKeyword)
+}
+
+// parseScheduleThe parses the remainder of "the [Nth] [last] <weekday|day>
+// of every month".
+func (p *parser) parseScheduleThe(s *Schedule) error {
+	s.unit = Month
+	n := 1
+	t := p.next()
+	if t.typ == tokenDigit {
+		var err error
+		n, err = p.parseScheduleOrdinal(t)
+		if err != nil {
+			return err
+		}
+		t = p.next()
+	}
+	last := t.typ == tokenKeyword && t.val == "last"
+	if last {
+		t = p.next()
+	}
+	switch {
+	case t.typ == tokenWeekday:
+		w, err := parseWeekday(t)
+		if err != nil {
+			return err
+		}
+		s.weekday = w
+		s.ordinal = n
+		if last {
+			s.ordinal = -n
+		}
+		t = p.next()
+	case t.typ == tokenUnit && strings.EqualFold(t.val, "day") && last:
+		s.day = -n
+		t = p.next()
+	case t.typ == tokenKeyword && t.val == "of" && !last:
+		s.day = n
+	default:
+		return newParseError(t, "unexpected token", tokenWeekday, tokenUnit)
+	}
+	if t.typ != tokenKeyword || t.val != "of" {
+		return newParseError(t, "unexpected token", tokenKeyword)
+	}
+	t = p.next()
+	if t.typ != tokenKeyword || t.val != "every" {
+		return newParseError(t, "unexpected token", tokenKeyword)
+	}
+	t = p.next()
+	if t.typ != tokenUnit || unitGranularity(t.val) != Month {
+		return newParseError(t, "unexpected token", tokenUnit)
+	}
+	return p.parseScheduleAt(s)
+}
+
+// parseScheduleOrdinal parses the ordinal number starting at digit d.
+func (p *parser) parseScheduleOrdinal(d token) (int, error) {
+	t := p.next()
+	if t.typ != tokenOrdinal {
+		return 0, newParseError(t, "unexpected token", tokenOrdinal)
+	}
+	n, err := strconv.Atoi(d.val)
+	if err != nil {
+		return 0, newParseError(d, err.Error())
+	}
+	if n < 1 || n > 31 {
+		return 0, newParseError(span(d, t), "ordinal out of range")
+	}
+	return n, nil
+}
+
+// parseScheduleAt parses the optional time of day of the occurrences.
+func (p *parser) parseScheduleAt(s *Schedule) error {
+	t := p.next()
+	if t.typ == tokenEOF {
+		return nil
+	}
+	if t.typ != tokenKeyword || t.val != "at" && t.val != "@" {
+		return newParseError(t, "unexpected token", tokenEOF, tokenKeyword)
+	}
+	err := p.parseKeywordAt()
+	if err != nil {
+		return err
+	}
+	if p.expr.Date != nil || len(p.expr.Offsets) > 0 {
+		end := p.tokens[len(p.tokens)-1]
+		return newParseError(span(t, end), "unexpected date in schedule")
+	}
+	c := p.expr.Clock
+	s.clock = true
+	s.hour, s.minute, s.second, s.nsec = c.Hour, c.Minute, c.Second, c.Nanosecond
+	return nil
+}
+
+func (p *parser) parseScheduleEOF() error {
+	t := p.next()
+	if t.typ != tokenEOF {
+		return newParseError(t, "unexpected token", tokenEOF)
+	}
+	return nil
+}
+
+// unitGranularity returns the granularity of a duration unit. Only the
+// single letter units are case sensitive, so "M" is a month and "m" is a
+// minute.
+func unitGranularity(unit string) Granularity {
+	if len(unit) > 1 {
+		unit = strings.ToLower(unit)
+	}
+	switch unit {
+	case "y", "year", "years":
+		return Year
+	case "M", "month", "months":
+		return Month
+	case "w", "week", "weeks":
+		return Week
+	case "d", "day", "days":
+		return Day
+	case "h", "hour", "hours":
+		return Hour
+	case "m", "minute", "minutes":
+		return Minute
+	case "ms", "millis", "millisecond", "milliseconds":
+		return Millisecond
+	case "us", "µs", "microsecond", "microseconds":
+		return Microsecond
+	case "ns", "nanosecond", "nanoseconds":
+		return Nanosecond
+	case "business day", "business days", "working day", "working days", "workday", "workdays":
+		return BusinessDay
+	case "business hour", "business hours", "working hour", "working hours":
+		return WorkingHour
+	}
+	return Second
+}
+
+// Next returns the first occurrence of the schedule after t, or the zero
+// time if there is none.
+func (s *Schedule) Next(after time.Time) time.Time {
+	if s.unit > Day {
+		step := time.Duration(s.every) * s.unit.add(time.Time{}, 1).Sub(time.Time{})
+		if after.Before(s.start) {
+			return s.start
+		}
+		n := after.Sub(s.start)/step + 1
+		return s.start.Add(n * step)
+	}
+	after = after.In(s.start.Location())
+	first := s.unit.truncate(s.start, s.weekStart, time.January)
+	n := s.periods(first, after)
+	n -= n%s.every + s.every
+	if n < 0 {
+		n = 0
+	}
+	for i := 0; i < maxPeriods; i++ {
+		t, ok := s.occurrence(s.unit.add(first, n))
+		if ok && t.After(after) {
+			return t
+		}
+		n += s.every
+	}
+	return time.Time{}
+}
+
+// Iter returns an iterator over the occurrences of the schedule after t.
+func (s *Schedule) Iter(after time.Time) *Iterator {
+	return &Iterator{s, after}
+}
+
+// periods returns the number of whole periods from first to t.
+func (s *Schedule) periods(first, t time.Time) int {
+	fy, fM, fd := first.Date()
+	ty, tM, td := t.Date()
+	switch s.unit {
+	case Year:
+		return ty - fy
+	case Month:
+		return (ty-fy)*12 + int(tM-fM)
+	}
+	a := time.Date(fy, fM, fd, 0, 0, 0, 0, time.UTC)
+	b := time.Date(ty, tM, td, 0, 0, 0, 0, time.UTC)
+	days := int(b.Sub(a).Hours() / 24)
+	if s.unit == Week {
+		return days / 7
+	}
+	return days
+}
+
+// occurrence returns the occurrence within the period starting at p, if
+// the period has one.
+func (s *Schedule) occurrence(p time.Time) (time.Time, bool) {
+	var t time.Time
+	switch s.unit {
+	case Year:
+		t = time.Date(p.Year(), s.start.Month(), s.day, 0, 0, 0, 0, p.Location())
+		if t.Month() != s.start.Month() {
+			return t, false
+		}
+	case Month:
+		switch {
+		case s.ordinal > 0:
+			t = nthWeekday(p, s.ordinal, s.weekday)
+		case s.ordinal < 0:
+			t = nthLastWeekday(p, -s.ordinal, s.weekday)
+		case s.day > 0:
+			t = p.AddDate(0, 0, s.day-1)
+		default:
+			t = p.AddDate(0, 1, s.day)
+		}
+		if t.Month() != p.Month() {
+			return t, false
+		}
+	case Week:
+		t = p.AddDate(0, 0, int(s.weekday-s.weekStart+7)%7)
+	default:
+		t = p
+	}
+	if s.clock {
+		y, M, d := t.Date()
+		t = time.Date(y, M, d, s.hour, s.minute, s.second, s.nsec, t.Location())
+	}
+	return t, true
+}
+
+// Iterator yields successive occurrences of a schedule.
+type Iterator struct {
+	s *Schedule
+	t time.Time
+}
+
+// Next returns the next occurrence of the schedule.
+func (it *Iterator) Next() time.Time {
+	it.t = it.s.Next(it.t)
+	return it.t
+}
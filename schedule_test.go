@@ -0,0 +1,168 @@
+package when
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	tests := []struct {
+		in   string
+		want []time.Time
+	}{
+		{
+			"every monday at 9am",
+			[]time.Time{
+				time.Date(2006, time.January, 9, 9, 0, 0, 0, loc),
+				time.Date(2006, time.January, 16, 9, 0, 0, 0, loc),
+				time.Date(2006, time.January, 23, 9, 0, 0, 0, loc),
+			},
+		},
+		{
+			"every 2 weeks on friday",
+			[]time.Time{
+				time.Date(2006, time.January, 6, 0, 0, 0, 0, loc),
+				time.Date(2006, time.January, 20, 0, 0, 0, 0, loc),
+				time.Date(2006, time.February, 3, 0, 0, 0, 0, loc),
+			},
+		},
+		{
+			"every 15m",
+			[]time.Time{
+				now.Add(15 * time.Minute),
+				now.Add(30 * time.Minute),
+				now.Add(45 * time.Minute),
+			},
+		},
+		{
+			"every day at 9am",
+			[]time.Time{
+				time.Date(2006, time.January, 3, 9, 0, 0, 0, loc),
+				time.Date(2006, time.January, 4, 9, 0, 0, 0, loc),
+				time.Date(2006, time.January, 5, 9, 0, 0, 0, loc),
+			},
+		},
+		{
+			"every other day",
+			[]time.Time{
+				time.Date(2006, time.January, 4, 0, 0, 0, 0, loc),
+				time.Date(2006, time.January, 6, 0, 0, 0, 0, loc),
+				time.Date(2006, time.January, 8, 0, 0, 0, 0, loc),
+			},
+		},
+		{
+			"every month on the 31st at noon",
+			[]time.Time{
+				time.Date(2006, time.January, 31, 12, 0, 0, 0, loc),
+				time.Date(2006, time.March, 31, 12, 0, 0, 0, loc),
+				time.Date(2006, time.May, 31, 12, 0, 0, 0, loc),
+			},
+		},
+		{
+			"every year",
+			[]time.Time{
+				time.Date(2007, time.January, 2, 0, 0, 0, 0, loc),
+				time.Date(2008, time.January, 2, 0, 0, 0, 0, loc),
+				time.Date(2009, time.January, 2, 0, 0, 0, 0, loc),
+			},
+		},
+		{
+			"on the last friday of every month",
+			[]time.Time{
+				time.Date(2006, time.January, 27, 0, 0, 0, 0, loc),
+				time.Date(2006, time.February, 24, 0, 0, 0, 0, loc),
+				time.Date(2006, time.March, 31, 0, 0, 0, 0, loc),
+			},
+		},
+		{
+			"the 2nd Tuesday of every month at 6:30pm",
+			[]time.Time{
+				time.Date(2006, time.January, 10, 18, 30, 0, 0, loc),
+				time.Date(2006, time.February, 14, 18, 30, 0, 0, loc),
+				time.Date(2006, time.March, 14, 18, 30, 0, 0, loc),
+			},
+		},
+		{
+			"5th Sunday of every month",
+			[]time.Time{
+				time.Date(2006, time.January, 29, 0, 0, 0, 0, loc),
+				time.Date(2006, time.April, 30, 0, 0, 0, 0, loc),
+				time.Date(2006, time.July, 30, 0, 0, 0, 0, loc),
+			},
+		},
+		{
+			"the 2nd last day of every month",
+			[]time.Time{
+				time.Date(2006, time.January, 30, 0, 0, 0, 0, loc),
+				time.Date(2006, time.February, 27, 0, 0, 0, 0, loc),
+				time.Date(2006, time.March, 30, 0, 0, 0, 0, loc),
+			},
+		},
+		{
+			"the 15th of every month",
+			[]time.Time{
+				time.Date(2006, time.January, 15, 0, 0, 0, 0, loc),
+				time.Date(2006, time.February, 15, 0, 0, 0, 0, loc),
+				time.Date(2006, time.March, 15, 0, 0, 0, 0, loc),
+			},
+		},
+	}
+	for _, tt := range tests {
+		s, err := ParseScheduleNow(tt.in, now)
+		if err != nil {
+			t.Fatalf("ParseSchedule(%q) %v", tt.in, err)
+		}
+		it := s.Iter(now)
+		have := make([]time.Time, len(tt.want))
+		for i := range have {
+			have[i] = it.Next()
+		}
+		if !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("ParseSchedule(%q)\nhave %v\nwant %v", tt.in, have, tt.want)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	s, err := ParseScheduleNow("every 2 weeks on friday at 9am", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	after := time.Date(2006, time.March, 4, 0, 0, 0, 0, time.UTC)
+	have := s.Next(after)
+	want := time.Date(2006, time.March, 17, 9, 0, 0, 0, time.UTC)
+	if !have.Equal(want) {
+		t.Errorf("Next(%v)\nhave %v\nwant %v", after, have, want)
+	}
+}
+
+func TestParseScheduleError(t *testing.T) {
+	var tests = []string{
+		"",
+		"every",
+		"every 0 days",
+		"every monday at 9am tomorrow",
+		"every 2 hours at 9am",
+		"every week on the 3rd",
+		"every month on friday",
+		"the 2nd Tuesday of every week",
+		"the 32nd of every month",
+		"monday",
+		"every day at 1/2",
+		"every day at 01/02/2006",
+	}
+	now := time.Now()
+	for _, tc := range tests {
+		have, err := ParseScheduleNow(tc, now)
+		if err == nil {
+			t.Errorf("ParseSchedule(%q)\nhave %v\nwant parse error", tc, have)
+		}
+	}
+}
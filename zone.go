@@ -0,0 +1,127 @@
+package when
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// zones maps time zone abbreviations to their offsets east of UTC in
+// seconds. Abbreviations shared by several zones, such as IST, are
+// omitted in favor of IANA names.
+var zones = map[string]int{
+	"GMT":  0,
+	"WET":  0,
+	"WEST": 1 * 3600,
+	"BST":  1 * 3600,
+	"CET":  1 * 3600,
+	"CEST": 2 * 3600,
+	"EET":  2 * 3600,
+	"EEST": 3 * 3600,
+	"MSK":  3 * 3600,
+	"JST":  9 * 3600,
+	"KST":  9 * 3600,
+	"AEST": 10 * 3600,
+	"AEDT": 11 * 3600,
+	"NZST": 12 * 3600,
+	"NZDT": 13 * 3600,
+	"HST":  -10 * 3600,
+	"AKST": -9 * 3600,
+	"AKDT": -8 * 3600,
+	"PST":  -8 * 3600,
+	"PDT":  -7 * 3600,
+	"MST":  -7 * 3600,
+	"MDT":  -6 * 3600,
+	"CST":  -6 * 3600,
+	"CDT":  -5 * 3600,
+	"EST":  -5 * 3600,
+	"EDT":  -4 * 3600,
+}
+
+// loadZone returns the location named by a time zone abbreviation such
+// as "PST", a numeric offset such as "+05:30" or an IANA name such as
+// "Europe/London".
+func loadZone(name string) (*time.Location, error) {
+	s := strings.ToUpper(name)
+	if s == "UTC" {
+		return time.UTC, nil
+	}
+	if offset, ok := zones[s]; ok {
+		return time.FixedZone(s, offset), nil
+	}
+	if n, offset := zoneOffset(name); n > 0 && n == len(name) {
+		return time.FixedZone(formatOffset(offset), offset), nil
+	}
+	if !strings.Contains(name, "/") {
+		return nil, fmt.Errorf("unknown time zone %s", name)
+	}
+	return time.LoadLocation(name)
+}
+
+// isZone reports whether s is a known time zone abbreviation.
+func isZone(s string) bool {
+	s = strings.ToUpper(s)
+	_, ok := zones[s]
+	return ok || s == "UTC"
+}
+
+// zoneOffset returns the length of the numeric offset at the start of s,
+// written as +hh, +hhmm or +hh:mm, and its value in seconds. The length
+// is zero if s does not start with an offset.
+func zoneOffset(s string) (int, int) {
+	if len(s) < 3 || s[0] != '+' && s[0] != '-' {
+		return 0, 0
+	}
+	digits := func(s string) (int, bool) {
+		if len(s) < 2 || !isDigit(s[0]) || !isDigit(s[1]) {
+			return 0, false
+		}
+		return int(s[0]-'0')*10 + int(s[1]-'0'), true
+	}
+	h, ok := digits(s[1:])
+	if !ok {
+		return 0, 0
+	}
+	n, m := 3, 0
+	switch {
+	case len(s) > 3 && s[3] == ':':
+		if m, ok = digits(s[4:]); !ok {
+			return 0, 0
+		}
+		n = 6
+	case len(s) > 3 && isDigit(s[3]):
+		if m, ok = digits(s[3:]); !ok {
+			return 0, 0
+		}
+		n = 5
+	}
+	if h > 14 || m > 59 {
+		return 0, 0
+	}
+	if n < len(s) && (isDigit(s[n]) || s[n] == ':' || isLetter(s[n])) {
+		return 0, 0
+	}
+	offset := (h*60 + m) * 60
+	if s[0] == '-' {
+		offset = -offset
+	}
+	return n, offset
+}
+
+// formatOffset returns an offset east of UTC in seconds as +hh:mm.
+func formatOffset(offset int) string {
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	return fmt.Sprintf("%c%02d:%02d", sign, offset/3600, offset%3600/60)
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
@@ -0,0 +1,87 @@
+package when
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseZone(t *testing.T) {
+	loc, err := time.LoadLocation("MST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2006, time.January, 2, 15, 4, 5, 0, loc)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"3pm PST", time.Date(2006, time.January, 2, 15, 0, 0, 0, time.FixedZone("PST", -8*3600))},
+		{"3pm pst", time.Date(2006, time.January, 2, 15, 0, 0, 0, time.FixedZone("PST", -8*3600))},
+		{"noon Europe/London", time.Date(2006, time.January, 2, 12, 0, 0, 0, london)},
+		{"14:00 UTC", time.Date(2006, time.January, 2, 14, 0, 0, 0, time.UTC)},
+		{"9am +05:30", time.Date(2006, time.January, 3, 9, 0, 0, 0, time.FixedZone("", 19800))},
+		{"9am -0800 + 1 hour", time.Date(2006, time.January, 2, 10, 0, 0, 0, time.FixedZone("", -8*3600))},
+		{"tomorrow at 3pm EST", time.Date(2006, time.January, 3, 15, 0, 0, 0, time.FixedZone("EST", -5*3600))},
+		// It is already January 3rd in Tokyo.
+		{"3pm JST today", time.Date(2006, time.January, 3, 15, 0, 0, 0, time.FixedZone("JST", 9*3600))},
+		{"3pm JST tomorrow", time.Date(2006, time.January, 4, 15, 0, 0, 0, time.FixedZone("JST", 9*3600))},
+		{"2 hours before Jan 3rd UTC", time.Date(2006, time.January, 2, 22, 0, 0, 0, time.UTC)},
+		{"3pm +10 hours", time.Date(2006, time.January, 3, 1, 0, 0, 0, loc)},
+		{"3pm -2 days", time.Date(2005, time.December, 31, 15, 0, 0, 0, loc)},
+		{"3pm -02 days", time.Date(2005, time.December, 31, 15, 0, 0, 0, loc)},
+		{"3pm +10", time.Date(2006, time.January, 3, 15, 0, 0, 0, time.FixedZone("", 10*3600))},
+	}
+	for _, tt := range tests {
+		have, err := ParseNow(tt.in, now)
+		if err != nil {
+			t.Errorf("ParseNow(%q)\nunexpected error: %v", tt.in, err)
+			continue
+		}
+		if !have.Equal(tt.want) {
+			t.Errorf("ParseNow(%q)\nhave %v\nwant %v", tt.in, have, tt.want)
+		}
+		if have.Location() != loc {
+			t.Errorf("ParseNow(%q) location\nhave %v\nwant %v", tt.in, have.Location(), loc)
+		}
+	}
+}
+
+func TestParseZoneError(t *testing.T) {
+	tests := []string{
+		"3pm PST UTC",
+		"3pm PST tomorrow UTC",
+		"PST",
+	}
+	for _, tt := range tests {
+		have, err := ParseExpr(tt)
+		if err == nil {
+			t.Errorf("ParseExpr(%q)\nhave %v\nwant parse error", tt, have)
+		}
+	}
+}
+
+func TestFormatZone(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"3pm pst", "3pm PST"},
+		{"9am +0530", "9am +05:30"},
+		{"noon Europe/London tomorrow", "tomorrow at noon Europe/London"},
+		{"14:00 utc + 2h", "2:00pm UTC + 2 hours"},
+	}
+	for _, tt := range tests {
+		e, err := ParseExpr(tt.in)
+		if err != nil {
+			t.Errorf("ParseExpr(%q)\nunexpected error: %v", tt.in, err)
+			continue
+		}
+		if have := Format(e); have != tt.want {
+			t.Errorf("Format(%q)\nhave %q\nwant %q", tt.in, have, tt.want)
+		}
+	}
+}